@@ -0,0 +1,60 @@
+// Command fakeenrich serves genderize, agify and nationalize compatible
+// endpoints from a fixture file for local runs and CI. Point the providers at
+// it with GENDERIZE_BASE_URL=http://localhost:8090/genderize and the like.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", ":8090", "listen address")
+		fixtures = flag.String("fixtures", "", "fixture file, the embedded fixtures when empty")
+		faults   enrichtest.Faults
+	)
+	flag.DurationVar(&faults.Latency, "latency", 0, "delay of every answer")
+	flag.IntVar(&faults.RateLimitEvery, "rate-limit-every", 0, "answer every n-th request with 429")
+	flag.IntVar(&faults.ServerErrorEvery, "server-error-every", 0, "answer every n-th request with 503")
+	flag.IntVar(&faults.MalformedEvery, "malformed-every", 0, "answer every n-th request with malformed JSON")
+	flag.IntVar(&faults.Quota, "quota", 0, "names served per provider before rate limiting, 0 for no limit")
+	flag.Parse()
+
+	loaded, err := enrichtest.LoadFixtures(*fixtures)
+	if err != nil {
+		slog.Error("failed to load fixtures", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           enrichtest.NewServer(loaded, faults),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("fake enrichment server shutdown", slog.String("error", err.Error()))
+		}
+	}()
+
+	slog.Info("fake enrichment server started", slog.String("addr", *addr), slog.Int("fixtures", len(loaded)))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("fake enrichment server", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
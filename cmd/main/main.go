@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Sanchir01/users-info/internal/app"
+	"github.com/Sanchir01/users-info/internal/feature/user"
 	httpserver "github.com/Sanchir01/users-info/internal/server/http"
 	httphandlers "github.com/Sanchir01/users-info/internal/server/http/handlers"
 	"github.com/fatih/color"
@@ -43,6 +44,20 @@ func main() {
 		slog.String("port", env.Config.Port),
 	)
 
+	go env.Services.UserService.RunEnrichmentWorkers(ctx, env.Config.Enrichment.Workers)
+	if reenrich := env.Config.Enrichment.Reenrich; reenrich.Enabled {
+		concurrency := reenrich.Concurrency
+		if batch := env.Config.Enrichment.Batch; batch.Enabled {
+			// keep a full provider batch in flight so the batcher coalesces the page
+			concurrency = max(concurrency, batch.Size)
+		}
+		go env.Services.UserService.RunReenrichment(ctx, user.ReenrichmentSettings{
+			Interval:    reenrich.Interval,
+			StaleAfter:  reenrich.StaleAfter,
+			Concurrency: concurrency,
+			BatchSize:   reenrich.BatchSize,
+		})
+	}
 	go func() {
 		if err := serverrest.Run(httphandlers.StartHTTTPHandlers(env.Handlers)); err != nil {
 			if !errors.Is(err, context.Canceled) {
@@ -19,9 +19,100 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/enrichment/quota": {
+            "get": {
+                "description": "get the remaining request budget of every enrichment provider",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.GetQuotaResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrich/preview": {
+            "post": {
+                "description": "preview the enrichment of a person without creating a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "preview body",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.PreviewEnrichmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.PreviewEnrichmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
         "/users": {
             "get": {
-                "description": "get all users",
+                "description": "get all users, list filters may be repeated or comma separated",
                 "consumes": [
                     "application/json"
                 ],
@@ -50,16 +141,119 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
+                        "minimum": 0,
                         "type": "integer",
                         "description": "minimum age filter",
                         "name": "min_age",
                         "in": "query"
                     },
                     {
+                        "minimum": 0,
                         "type": "integer",
                         "description": "maximum age filter",
                         "name": "max_age",
                         "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "male",
+                                "female",
+                                "unknown"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "gender filter",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ISO 3166-1 alpha-2 nationality filter",
+                        "name": "nationality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exact name, case-insensitive",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name prefix, case-insensitive",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exact surname, case-insensitive",
+                        "name": "surname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "surname prefix, case-insensitive",
+                        "name": "surname_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exact patronymic, case-insensitive",
+                        "name": "patronymic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "patronymic prefix, case-insensitive",
+                        "name": "patronymic_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or after, RFC 3339 or date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created at or before, RFC 3339 or date",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated at or after, RFC 3339 or date",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "updated at or before, RFC 3339 or date",
+                        "name": "updated_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "pending",
+                                "done",
+                                "partial",
+                                "failed"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "enrichment status filter",
+                        "name": "enrichment_status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -72,19 +266,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     }
                 }
@@ -123,25 +317,72 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     }
                 }
             }
         },
         "/users/{id}": {
+            "get": {
+                "description": "get user by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.GetUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            },
             "delete": {
                 "description": "delete user by id",
                 "consumes": [
@@ -172,19 +413,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     }
                 }
@@ -228,73 +469,359 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.Response"
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.Response": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
         },
-        "user.CreateUserRequest": {
-            "type": "object",
-            "required": [
-                "name",
-                "surname"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
-                },
-                "patronymic": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "surname": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+        "/users/{id}/enrichment": {
+            "get": {
+                "description": "get enrichment details (confidence, sample size, all country predictions) of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.GetUserEnrichmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
                 }
             }
         },
-        "user.CreateUserResponse": {
-            "type": "object",
-            "required": [
-                "ok"
-            ],
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "ok": {
-                    "type": "string"
-                },
-                "status": {
+        "/users/{id}/enrichment/history": {
+            "get": {
+                "description": "get the enrichment history of a user, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "items per page",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.GetUserEnrichmentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "admin.GetQuotaResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.QuotaBudget"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "enrich.CountryPrediction": {
+            "type": "object",
+            "properties": {
+                "country_id": {
+                    "type": "string"
+                },
+                "probability": {
+                    "type": "number"
+                }
+            }
+        },
+        "enrich.Field": {
+            "type": "string",
+            "enum": [
+                "gender",
+                "age",
+                "nationality"
+            ],
+            "x-enum-varnames": [
+                "FieldGender",
+                "FieldAge",
+                "FieldNationality"
+            ]
+        },
+        "enrich.LocalizationMode": {
+            "type": "string",
+            "enum": [
+                "global",
+                "hint",
+                "predicted"
+            ],
+            "x-enum-varnames": [
+                "ModeGlobal",
+                "ModeHint",
+                "ModePredicted"
+            ]
+        },
+        "enrich.Prediction": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Cached is set when the answer was served from the cache.",
+                    "type": "boolean"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.CountryPrediction"
+                    }
+                },
+                "country_id": {
+                    "description": "CountryID is the country the prediction was localized for, empty if global.",
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is the name the provider was asked about.",
+                    "type": "string"
+                },
+                "probability": {
+                    "description": "Probability is nil for providers that do not report one (agify).",
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value is the predicted gender, age or top country as text.",
+                    "type": "string"
+                }
+            }
+        },
+        "enrich.QuotaBudget": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "reset_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "surname"
+            ],
+            "properties": {
+                "country_hint": {
+                    "description": "CountryHint localizes age and gender predictions, e.g. \"RU\"",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "patronymic": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "surname": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "user.CreateUserResponse": {
+            "type": "object",
+            "required": [
+                "ok"
+            ],
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
@@ -316,6 +843,91 @@ const docTemplate = `{
                 }
             }
         },
+        "user.EnrichmentDB": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.CountryPrediction"
+                    }
+                },
+                "country_id": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "input_name": {
+                    "type": "string"
+                },
+                "probability": {
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.EnrichmentHistoryDB": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.CountryPrediction"
+                    }
+                },
+                "country_id": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "input_name": {
+                    "type": "string"
+                },
+                "probability": {
+                    "type": "number"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.EnrichmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "done",
+                "partial",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentPending",
+                "EnrichmentDone",
+                "EnrichmentPartial",
+                "EnrichmentFailed"
+            ]
+        },
         "user.GetAllUsersResponse": {
             "type": "object",
             "properties": {
@@ -339,6 +951,143 @@ const docTemplate = `{
                 }
             }
         },
+        "user.GetUserEnrichmentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.EnrichmentHistoryDB"
+                    }
+                },
+                "items_per_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.GetUserEnrichmentResponse": {
+            "type": "object",
+            "properties": {
+                "enrichment": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.EnrichmentDB"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.GetUserResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/user.UserDB"
+                }
+            }
+        },
+        "user.PreviewEnrichmentRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "surname"
+            ],
+            "properties": {
+                "country_hint": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "patronymic": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "surname": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "user.PreviewEnrichmentResponse": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "cached_fields": {
+                    "description": "CachedFields lists the fields whose value was served from the cache.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.Field"
+                    }
+                },
+                "enrichment_mode": {
+                    "$ref": "#/definitions/enrich.LocalizationMode"
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/user.EnrichmentStatus"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                },
+                "predictions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.Prediction"
+                    }
+                },
+                "sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "unenriched_fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.Field"
+                    }
+                }
+            }
+        },
         "user.UpdateUserRequest": {
             "type": "object",
             "required": [
@@ -346,19 +1095,49 @@ const docTemplate = `{
                 "surname"
             ],
             "properties": {
+                "age": {
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 0
+                },
+                "country_hint": {
+                    "description": "CountryHint replaces the stored hint when present.",
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female",
+                        "unknown"
+                    ]
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
                 },
+                "nationality": {
+                    "type": "string"
+                },
                 "patronymic": {
                     "type": "string",
                     "maxLength": 100
                 },
+                "reenrich": {
+                    "description": "Reenrich asks the providers again even if the name did not change.",
+                    "type": "boolean"
+                },
                 "surname": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
+                },
+                "unlock": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.Field"
+                    }
                 }
             }
         },
@@ -368,6 +1147,12 @@ const docTemplate = `{
                 "ok"
             ],
             "properties": {
+                "enrichment_mode": {
+                    "$ref": "#/definitions/enrich.LocalizationMode"
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/user.EnrichmentStatus"
+                },
                 "error": {
                     "type": "string"
                 },
@@ -376,6 +1161,12 @@ const docTemplate = `{
                 },
                 "status": {
                     "type": "string"
+                },
+                "unenriched_fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/enrich.Field"
+                    }
                 }
             }
         },
@@ -385,12 +1176,37 @@ const docTemplate = `{
                 "age": {
                     "type": "integer"
                 },
+                "age_manual": {
+                    "type": "boolean"
+                },
+                "country_hint": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "enriched_at": {
+                    "type": "string"
+                },
+                "enrichment_mode": {
+                    "type": "string"
+                },
+                "enrichment_sources": {
+                    "description": "EnrichmentSources names the provider, rule or \"manual\" each field came from.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/user.EnrichmentStatus"
+                },
                 "gender": {
                     "type": "string"
                 },
+                "gender_manual": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -400,12 +1216,21 @@ const docTemplate = `{
                 "nationality": {
                     "type": "string"
                 },
+                "nationality_manual": {
+                    "type": "boolean"
+                },
                 "patronymic": {
                     "type": "string"
                 },
                 "surname": {
                     "type": "string"
                 },
+                "unenriched_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
                 },
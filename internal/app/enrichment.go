@@ -0,0 +1,204 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Sanchir01/users-info/internal/config"
+	"github.com/Sanchir01/users-info/internal/enrich"
+)
+
+const (
+	enrichmentRemote   = "remote"
+	enrichmentOffline  = "offline"
+	enrichmentFallback = "fallback"
+
+	genderRulesBefore  = "before"
+	genderRulesInstead = "instead"
+	genderRulesOff     = "off"
+)
+
+// NewEnricher builds the enrichment pipeline selected by cfg.Enrichment.Mode:
+// the remote providers, the embedded names dictionary, or the remote
+// providers falling back to the dictionary when they are unavailable.
+// A non-nil quota tracks and enforces the budgets of the remote providers.
+func NewEnricher(cfg *config.Config, db *Database, quota *enrich.Quota) (enrich.Enricher, error) {
+	mode := cfg.Enrichment.Mode
+	if mode != enrichmentRemote && mode != enrichmentOffline && mode != enrichmentFallback {
+		return nil, fmt.Errorf("unknown enrichment mode %q", mode)
+	}
+	rules := cfg.Enrichment.GenderRules
+	if rules != genderRulesBefore && rules != genderRulesInstead && rules != genderRulesOff {
+		return nil, fmt.Errorf("unknown gender rules mode %q", rules)
+	}
+	scheme, err := enrich.ParseTransliteration(cfg.Enrichment.Translit)
+	if err != nil {
+		return nil, err
+	}
+	providers := remoteProviders(cfg.Enrichment.Providers)
+	enabled := 0
+	for _, p := range providers {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if p.cfg.Enabled {
+			enabled++
+		}
+	}
+	if mode == enrichmentRemote && enabled == 0 {
+		return nil, fmt.Errorf("enrichment mode %q needs at least one enabled provider", mode)
+	}
+	// genderResolver applies the patronymic and surname rules to the gender provider.
+	genderResolver := func(e enrich.Enricher) enrich.Enricher {
+		switch rules {
+		case genderRulesBefore:
+			return enrich.NewGenderRules(e)
+		case genderRulesInstead:
+			return enrich.NewGenderRules(nil)
+		}
+		return e
+	}
+
+	var dict *enrich.Dictionary
+	if mode != enrichmentRemote {
+		if dict, err = enrich.LoadDictionary(scheme); err != nil {
+			return nil, err
+		}
+	}
+	if mode == enrichmentOffline {
+		offline := func(field enrich.Field) enrich.Enricher {
+			return enrich.NewNormalizer(enrich.NewOffline(dict, field), scheme)
+		}
+		return enrich.NewRegistry(
+			offline(enrich.FieldNationality),
+			genderResolver(offline(enrich.FieldGender)),
+			offline(enrich.FieldAge),
+		), nil
+	}
+
+	retryPolicy := enrich.RetryPolicy{
+		MaxAttempts: cfg.Enrichment.Retry.MaxAttempts,
+		BaseDelay:   cfg.Enrichment.Retry.BaseDelay,
+		MaxDelay:    cfg.Enrichment.Retry.MaxDelay,
+	}
+	breakerSettings := enrich.BreakerSettings{
+		FailureThreshold: cfg.Enrichment.Breaker.FailureThreshold,
+		OpenTimeout:      cfg.Enrichment.Breaker.OpenTimeout,
+	}
+	// normalizer -> fallback -> thresholds -> cache -> quota -> circuit breaker -> retry -> batcher -> provider
+	provider := func(p remoteProvider) enrich.Enricher {
+		transport := enrich.LimitTransport(p.cfg.Concurrency, nil)
+		if quota != nil {
+			transport = quota.Transport(p.name, transport)
+		}
+		client := &http.Client{Timeout: p.cfg.Timeout, Transport: transport}
+		b := p.build(client, enrich.Endpoint{BaseURL: p.cfg.BaseURL, APIKey: p.cfg.APIKey})
+
+		var e enrich.Enricher = b
+		if cfg.Enrichment.Batch.Enabled {
+			e = enrich.NewBatcher(b, cfg.Enrichment.Batch.Window, cfg.Enrichment.Batch.Size)
+		}
+		e = enrich.NewRetry(e, retryPolicy)
+		e = enrich.NewBreaker(e, breakerSettings)
+		if quota != nil {
+			e = quota.Guard(e)
+		}
+		e = enrich.NewCache(e, db.RedisDB, cfg.Enrichment.Cache.TTL, cfg.Enrichment.Cache.Bypass)
+		e = enrich.NewThreshold(e, enrich.Thresholds{MinProbability: p.cfg.MinProb, MinCount: p.cfg.MinCount})
+		if mode == enrichmentFallback {
+			e = enrich.NewFallback(e, enrich.NewOffline(dict, p.field))
+		}
+		return enrich.NewNormalizer(e, scheme)
+	}
+
+	enrichers := enrich.NewRegistry()
+	for _, p := range providers {
+		var e enrich.Enricher
+		switch {
+		case p.cfg.Enabled:
+			e = provider(p)
+		case mode == enrichmentFallback:
+			// a disabled provider is replaced by the dictionary
+			e = enrich.NewNormalizer(enrich.NewOffline(dict, p.field), scheme)
+		case p.field == enrich.FieldGender && rules != genderRulesOff:
+			// without a provider the rules are the only source of gender
+			enrichers.RegisterLocalized(enrich.NewGenderRules(nil))
+			continue
+		default:
+			continue
+		}
+		switch p.field {
+		case enrich.FieldNationality:
+			enrichers.Register(e)
+		case enrich.FieldGender:
+			enrichers.RegisterLocalized(genderResolver(e))
+		default:
+			enrichers.RegisterLocalized(e)
+		}
+	}
+	enrichers.SetCountryPrediction(cfg.Enrichment.PredictCountry)
+	return enrichers, nil
+}
+
+type remoteProvider struct {
+	name  string
+	field enrich.Field
+	cfg   config.Provider
+	build func(*http.Client, enrich.Endpoint) enrich.BatchEnricher
+}
+
+func remoteProviders(cfg config.Providers) []remoteProvider {
+	return []remoteProvider{
+		{
+			name:  "nationalize",
+			field: enrich.FieldNationality,
+			cfg:   cfg.Nationalize,
+			build: func(c *http.Client, e enrich.Endpoint) enrich.BatchEnricher {
+				return enrich.NewNationalize(c, e)
+			},
+		},
+		{
+			name:  "genderize",
+			field: enrich.FieldGender,
+			cfg:   cfg.Genderize,
+			build: func(c *http.Client, e enrich.Endpoint) enrich.BatchEnricher {
+				return enrich.NewGenderize(c, e)
+			},
+		},
+		{
+			name:  "agify",
+			field: enrich.FieldAge,
+			cfg:   cfg.Agify,
+			build: func(c *http.Client, e enrich.Endpoint) enrich.BatchEnricher {
+				return enrich.NewAgify(c, e)
+			},
+		},
+	}
+}
+
+// validate checks the settings of an enabled provider.
+func (p remoteProvider) validate() error {
+	if !p.cfg.Enabled {
+		return nil
+	}
+	if p.cfg.BaseURL != "" {
+		u, err := url.Parse(p.cfg.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("provider %s: invalid base url %q", p.name, p.cfg.BaseURL)
+		}
+	}
+	if p.cfg.Timeout <= 0 {
+		return fmt.Errorf("provider %s: timeout must be positive, got %s", p.name, p.cfg.Timeout)
+	}
+	if p.cfg.MinProb < 0 || p.cfg.MinProb > 1 {
+		return fmt.Errorf("provider %s: min probability must be within [0, 1], got %v", p.name, p.cfg.MinProb)
+	}
+	if p.cfg.MinCount < 0 {
+		return fmt.Errorf("provider %s: min count must not be negative, got %d", p.name, p.cfg.MinCount)
+	}
+	if p.cfg.Concurrency < 0 {
+		return fmt.Errorf("provider %s: concurrency must not be negative, got %d", p.name, p.cfg.Concurrency)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 
 	"github.com/Sanchir01/users-info/internal/config"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
 )
 
 type Env struct {
@@ -22,15 +23,22 @@ func NewEnv() (*Env, error) {
 	cfg := config.InitConfig()
 	fmt.Println(cfg.RedisDB)
 	lg := setupLogger(cfg.Env)
-	fmt.Println("config", cfg)
+	if err := api.SetDefaultLanguage(cfg.Language); err != nil {
+		lg.Error("language config error", slog.String("error", err.Error()))
+		return nil, err
+	}
 	pgxdb, err := NewDataBases(cfg)
 	if err != nil {
-		lg.Error("pgx error connect", err.Error())
+		lg.Error("pgx error connect", slog.String("error", err.Error()))
 		return nil, err
 	}
 
 	repos := NewRepositories(pgxdb)
-	servises := NewServices(repos, pgxdb)
+	servises, err := NewServices(repos, pgxdb, cfg)
+	if err != nil {
+		lg.Error("services init error", slog.String("error", err.Error()))
+		return nil, err
+	}
 	handlers := NewHandlers(servises, lg)
 
 	env := Env{
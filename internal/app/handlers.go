@@ -1,16 +1,19 @@
 package app
 
 import (
+	"github.com/Sanchir01/users-info/internal/feature/admin"
 	"github.com/Sanchir01/users-info/internal/feature/user"
 	"log/slog"
 )
 
 type Handlers struct {
-	UserHandler *user.Handler
+	UserHandler  *user.Handler
+	AdminHandler *admin.Handler
 }
 
 func NewHandlers(services *Services, lg *slog.Logger) *Handlers {
 	return &Handlers{
-		UserHandler: user.NewHandler(services.UserService, lg),
+		UserHandler:  user.NewHandler(services.UserService, lg),
+		AdminHandler: admin.NewHandler(services.Quota, lg),
 	}
 }
@@ -1,13 +1,27 @@
 package app
 
-import "github.com/Sanchir01/users-info/internal/feature/user"
+import (
+	"github.com/Sanchir01/users-info/internal/config"
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/feature/user"
+)
 
 type Services struct {
 	UserService *user.Service
+	Quota       *enrich.Quota
 }
 
-func NewServices(repos *Repositories, db *Database) *Services {
-	return &Services{
-		UserService: user.NewService(repos.UserRepository, db.PrimaryDB),
+func NewServices(repos *Repositories, db *Database, cfg *config.Config) (*Services, error) {
+	var quota *enrich.Quota
+	if cfg.Enrichment.Quota.Enabled {
+		quota = enrich.NewQuota(db.RedisDB, cfg.Enrichment.Quota.Reserve)
+	}
+	enricher, err := NewEnricher(cfg, db, quota)
+	if err != nil {
+		return nil, err
 	}
+	return &Services{
+		UserService: user.NewService(repos.UserRepository, db.PrimaryDB, enricher, cfg.Enrichment.QueueSize),
+		Quota:       quota,
+	}, nil
 }
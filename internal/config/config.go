@@ -14,10 +14,12 @@ import (
 type Config struct {
 	Env        string `yaml:"env"`
 	Domain     string `yaml:"domain"`
+	Language   string `yaml:"language"  env-default:"en"`
 	HttpServer `yaml:"http_server"`
 	RedisDB    Redis      `yaml:"redis"`
 	DB         DataBase   `yaml:"database"`
 	Prometheus Prometheus `yaml:"prometheus"`
+	Enrichment Enrichment `yaml:"enrichment"`
 }
 type HttpServer struct {
 	Timeout     time.Duration `yaml:"timeout"  env-default:"4s"`
@@ -49,6 +51,65 @@ type Redis struct {
 	DBNumber int    `yaml:"dbnumber"`
 }
 
+type Enrichment struct {
+	Mode           string          `yaml:"mode"  env-default:"remote"`
+	Workers        int             `yaml:"workers"  env-default:"4"`
+	QueueSize      int             `yaml:"queue_size"  env-default:"1000"`
+	PredictCountry bool            `yaml:"predict_country"`
+	GenderRules    string          `yaml:"gender_rules"  env-default:"before"`
+	Translit       string          `yaml:"transliteration"  env-default:"gost"`
+	Cache          EnrichmentCache `yaml:"cache"`
+	Retry          Retry           `yaml:"retry"`
+	Breaker        Breaker         `yaml:"breaker"`
+	Batch          Batch           `yaml:"batch"`
+	Reenrich       Reenrich        `yaml:"reenrich"`
+	Quota          Quota           `yaml:"quota"`
+	Providers      Providers       `yaml:"providers"`
+}
+type EnrichmentCache struct {
+	TTL    time.Duration `yaml:"ttl"  env-default:"24h"`
+	Bypass bool          `yaml:"bypass"  env-default:"false"`
+}
+type Retry struct {
+	MaxAttempts int           `yaml:"max_attempts"  env-default:"3"`
+	BaseDelay   time.Duration `yaml:"base_delay"  env-default:"200ms"`
+	MaxDelay    time.Duration `yaml:"max_delay"  env-default:"5s"`
+}
+type Breaker struct {
+	FailureThreshold int           `yaml:"failure_threshold"  env-default:"5"`
+	OpenTimeout      time.Duration `yaml:"open_timeout"  env-default:"30s"`
+}
+type Batch struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"  env-default:"50ms"`
+	Size    int           `yaml:"size"  env-default:"10"`
+}
+type Reenrich struct {
+	Enabled     bool          `yaml:"enabled"`
+	Interval    time.Duration `yaml:"interval"  env-default:"1h"`
+	StaleAfter  time.Duration `yaml:"stale_after"  env-default:"720h"`
+	Concurrency int           `yaml:"concurrency"  env-default:"10"`
+	BatchSize   int           `yaml:"batch_size"  env-default:"100"`
+}
+type Providers struct {
+	Genderize   Provider `yaml:"genderize"  env-prefix:"GENDERIZE_"`
+	Agify       Provider `yaml:"agify"  env-prefix:"AGIFY_"`
+	Nationalize Provider `yaml:"nationalize"  env-prefix:"NATIONALIZE_"`
+}
+type Provider struct {
+	Enabled     bool          `yaml:"enabled"  env:"ENABLED"`
+	BaseURL     string        `yaml:"base_url"  env:"BASE_URL"`
+	APIKey      string        `yaml:"api_key"  env:"API_KEY"`
+	Timeout     time.Duration `yaml:"timeout"  env:"TIMEOUT"  env-default:"5s"`
+	Concurrency int           `yaml:"concurrency"  env:"CONCURRENCY"  env-default:"8"`
+	MinProb     float64       `yaml:"min_probability"  env:"MIN_PROBABILITY"  env-default:"0"`
+	MinCount    int           `yaml:"min_count"  env:"MIN_COUNT"  env-default:"0"`
+}
+type Quota struct {
+	Enabled bool `yaml:"enabled"`
+	Reserve int  `yaml:"reserve"  env-default:"10"`
+}
+
 func InitConfig() *Config {
 	envFile := os.Getenv("ENV_FILE")
 	if envFile == "" {
@@ -56,7 +117,7 @@ func InitConfig() *Config {
 	}
 	fmt.Println("env name", envFile)
 	if err := godotenv.Load(envFile); err != nil {
-		slog.Error("ошибка при инициализации переменных окружения", err.Error())
+		slog.Error("ошибка при инициализации переменных окружения", slog.String("error", err.Error()))
 	}
 	configPath := os.Getenv("CONFIG_PATH")
 
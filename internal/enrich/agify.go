@@ -0,0 +1,72 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AgifyURL is the public endpoint used when no base URL is configured.
+const AgifyURL = "https://api.agify.io/"
+
+// AgifyResponse has a nil Age when the name is unknown to agify.
+type AgifyResponse struct {
+	Name  string `json:"name" validate:"required,min=1,max=100"`
+	Age   *int   `json:"age" validate:"omitempty,gte=0,lte=120"`
+	Count int    `json:"count" validate:"gte=0"`
+}
+
+// Agify predicts age using https://agify.io.
+type Agify struct {
+	client   *http.Client
+	endpoint Endpoint
+}
+
+func NewAgify(client *http.Client, endpoint Endpoint) *Agify {
+	if endpoint.BaseURL == "" {
+		endpoint.BaseURL = AgifyURL
+	}
+	return &Agify{client: client, endpoint: endpoint}
+}
+
+func (a *Agify) Name() string {
+	return "agify"
+}
+
+func (a *Agify) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data AgifyResponse
+	if err := getJSON(ctx, a.client, a.Name(), a.endpoint.url(q.CountryID, q.Name), &data); err != nil {
+		return Result{}, err
+	}
+	return a.result(q, data)
+}
+
+func (a *Agify) EnrichBatch(ctx context.Context, qs []Query) ([]Result, error) {
+	return enrichBatch(ctx, a.client, a.Name(), a.endpoint, qs, a.result)
+}
+
+func (a *Agify) result(q Query, data AgifyResponse) (Result, error) {
+	if err := validateResponse(a.Name(), &data); err != nil {
+		return Result{}, err
+	}
+	var value string
+	if data.Age != nil {
+		value = strconv.Itoa(*data.Age)
+	}
+	res := Result{
+		Age: data.Age,
+		Predictions: []Prediction{{
+			Provider:  a.Name(),
+			Name:      q.Name,
+			CountryID: q.CountryID,
+			Value:     value,
+			Count:     data.Count,
+			FetchedAt: time.Now().UTC(),
+		}},
+	}
+	if data.Age != nil {
+		res.setSource(FieldAge, a.Name())
+	}
+	return res, nil
+}
@@ -0,0 +1,140 @@
+package enrich
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MaxBatchSize is the number of names genderize, agify and nationalize accept
+// in one request.
+const MaxBatchSize = 10
+
+// BatchEnricher is an Enricher that can answer several queries with a single
+// provider request. Results are returned in the order of qs.
+type BatchEnricher interface {
+	Enricher
+	EnrichBatch(ctx context.Context, qs []Query) ([]Result, error)
+}
+
+type batchResult struct {
+	res Result
+	err error
+}
+
+type batch struct {
+	queries []Query
+	waiters map[Query][]chan batchResult
+}
+
+// Batcher coalesces concurrent lookups into name[] requests. A batch is sent
+// once it holds size distinct queries or window has passed since its first
+// query, so bursts from the enrichment workers cost one call per provider
+// instead of one per name.
+type Batcher struct {
+	next   BatchEnricher
+	window time.Duration
+	size   int
+
+	mu      sync.Mutex
+	pending batch
+	timer   *time.Timer
+}
+
+func NewBatcher(next BatchEnricher, window time.Duration, size int) *Batcher {
+	if size <= 0 || size > MaxBatchSize {
+		size = MaxBatchSize
+	}
+	return &Batcher{next: next, window: window, size: size}
+}
+
+func (b *Batcher) Name() string {
+	return b.next.Name()
+}
+
+func (b *Batcher) Enrich(ctx context.Context, q Query) (Result, error) {
+	done := make(chan batchResult, 1)
+	// providers only look at the first name and the country
+	q = Query{Name: q.Name, CountryID: q.CountryID}
+
+	b.mu.Lock()
+	if b.pending.waiters == nil {
+		b.pending.waiters = make(map[Query][]chan batchResult)
+	}
+	if _, ok := b.pending.waiters[q]; !ok {
+		b.pending.queries = append(b.pending.queries, q)
+		if len(b.pending.queries) == 1 {
+			b.timer = time.AfterFunc(b.window, b.flushPending)
+		}
+	}
+	b.pending.waiters[q] = append(b.pending.waiters[q], done)
+	if len(b.pending.queries) >= b.size {
+		go b.flush(b.takeLocked())
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	case r := <-done:
+		return r.res, r.err
+	}
+}
+
+func (b *Batcher) takeLocked() batch {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	taken := b.pending
+	b.pending = batch{}
+	return taken
+}
+
+func (b *Batcher) flushPending() {
+	b.mu.Lock()
+	taken := b.takeLocked()
+	b.mu.Unlock()
+	b.flush(taken)
+}
+
+// flush sends the batch detached from the callers' contexts: a caller that
+// gives up must not cancel the lookup for the others. Queries for different
+// countries go in separate requests.
+func (b *Batcher) flush(taken batch) {
+	groups := make(map[string][]Query)
+	for _, q := range taken.queries {
+		groups[q.CountryID] = append(groups[q.CountryID], q)
+	}
+	for _, qs := range groups {
+		go b.send(qs, taken.waiters)
+	}
+}
+
+func (b *Batcher) send(qs []Query, waiters map[Query][]chan batchResult) {
+	batchSize.WithLabelValues(b.Name()).Observe(float64(len(qs)))
+
+	var (
+		results []Result
+		err     error
+	)
+	ctx := context.Background()
+	if len(qs) == 1 {
+		var res Result
+		res, err = b.next.Enrich(ctx, qs[0])
+		results = []Result{res}
+	} else {
+		results, err = b.next.EnrichBatch(ctx, qs)
+	}
+
+	// every waiter gets its own copy, decorators above may change it
+	for i, q := range qs {
+		for _, done := range waiters[q] {
+			r := batchResult{err: err}
+			if err == nil {
+				r.res = results[i].clone()
+			}
+			done <- r
+		}
+	}
+}
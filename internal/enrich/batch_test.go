@@ -0,0 +1,76 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+)
+
+func TestBatcherCoalescesConcurrentLookups(t *testing.T) {
+	baseURL, srv := startServer(t, enrichtest.Faults{})
+	c := defaultChain
+	c.batch = 50 * time.Millisecond
+	e := genderize(c, baseURL)
+
+	names := []string{"Дмитрий", "Сергей", "Юлия", "Ivan", "Aleksandr"}
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q := enrich.Query{Name: names[i%len(names)]}
+			res, err := e.Enrich(context.Background(), q)
+			if err == nil && res.Gender == "" {
+				err = fmt.Errorf("%s: no gender", q.Name)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1 batch", got)
+	}
+}
+
+func TestBatcherGivesEveryCallerItsOwnResult(t *testing.T) {
+	baseURL, _ := startServer(t, enrichtest.Faults{})
+	e := enrich.NewBatcher(
+		enrich.NewGenderize(http.DefaultClient, enrichtest.Endpoint(baseURL, "genderize")),
+		20*time.Millisecond, enrich.MaxBatchSize,
+	)
+
+	var wg sync.WaitGroup
+	results := make([]enrich.Result, 4)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := e.Enrich(context.Background(), enrich.Query{Name: "ivan"})
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	delete(results[0].Sources, enrich.FieldGender)
+	results[0].Predictions[0].Value = "changed"
+	for i, res := range results[1:] {
+		if res.Sources[enrich.FieldGender] != "genderize" || res.Predictions[0].Value != "male" {
+			t.Errorf("caller %d sees the changes of another caller: %v, %+v", i+1, res.Sources, res.Predictions)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitHalfOpen
+	CircuitOpen
+)
+
+type BreakerSettings struct {
+	// FailureThreshold is the number of consecutive transient failures that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a single probe call is let through.
+	OpenTimeout time.Duration
+}
+
+// Breaker stops calling a provider after repeated transient failures and fails
+// fast with ErrCircuitOpen until OpenTimeout passes.
+type Breaker struct {
+	next     Enricher
+	settings BreakerSettings
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(next Enricher, settings BreakerSettings) *Breaker {
+	b := &Breaker{next: next, settings: settings}
+	circuitState.WithLabelValues(next.Name()).Set(float64(CircuitClosed))
+	return b
+}
+
+func (b *Breaker) Name() string {
+	return b.next.Name()
+}
+
+func (b *Breaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) Enrich(ctx context.Context, q Query) (Result, error) {
+	if !b.allow() {
+		return Result{}, ErrCircuitOpen
+	}
+	res, err := b.next.Enrich(ctx, q)
+	b.record(err)
+	return res, err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		b.probing = true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// the caller gave up, this says nothing about the provider
+	case isTransient(err):
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.settings.FailureThreshold {
+			b.openedAt = time.Now()
+			b.setState(CircuitOpen)
+		}
+	default:
+		b.failures = 0
+		b.setState(CircuitClosed)
+	}
+}
+
+func (b *Breaker) setState(state CircuitState) {
+	b.state = state
+	circuitState.WithLabelValues(b.Name()).Set(float64(state))
+}
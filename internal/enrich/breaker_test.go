@@ -0,0 +1,39 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+)
+
+func TestBreakerOpensOnServerErrors(t *testing.T) {
+	baseURL, srv := startServer(t, enrichtest.Faults{ServerErrorEvery: 1})
+	c := chain{
+		retry:   enrich.RetryPolicy{MaxAttempts: 1},
+		breaker: enrich.BreakerSettings{FailureThreshold: 2, OpenTimeout: time.Minute},
+	}
+	e := genderize(c, baseURL)
+
+	for range 2 {
+		_, err := e.Enrich(context.Background(), enrich.Query{Name: "Ivan"})
+		var statusErr *enrich.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("got %v, want a 503 status error", err)
+		}
+	}
+	_, err := e.Enrich(context.Background(), enrich.Query{Name: "Ivan"})
+	if !errors.Is(err, enrich.ErrCircuitOpen) {
+		t.Fatalf("got %v, want %v", err, enrich.ErrCircuitOpen)
+	}
+	if !enrich.Unavailable(err) {
+		t.Errorf("open circuit is not reported as unavailable")
+	}
+	if got := srv.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2: the open circuit must not reach the provider", got)
+	}
+}
@@ -0,0 +1,104 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const cacheKeyPrefix = "enrich"
+
+type bypassCacheKey struct{}
+
+// WithoutCache returns a context that makes Cache skip the lookup and always
+// ask the wrapped provider. Fresh results are still written back.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// Cache stores the results of a single provider in Redis keyed by the
+// normalized first name. Redis failures are logged and never fail the lookup.
+type Cache struct {
+	next   Enricher
+	rdb    *redis.Client
+	ttl    time.Duration
+	bypass bool
+}
+
+func NewCache(next Enricher, rdb *redis.Client, ttl time.Duration, bypass bool) *Cache {
+	return &Cache{next: next, rdb: rdb, ttl: ttl, bypass: bypass}
+}
+
+func (c *Cache) Name() string {
+	return c.next.Name()
+}
+
+func (c *Cache) Enrich(ctx context.Context, q Query) (Result, error) {
+	key := c.key(q)
+
+	if c.bypass || cacheBypassed(ctx) {
+		cacheRequests.WithLabelValues(c.Name(), "bypass").Inc()
+	} else {
+		res, ok := c.get(ctx, key)
+		if ok {
+			cacheRequests.WithLabelValues(c.Name(), "hit").Inc()
+			return res, nil
+		}
+		cacheRequests.WithLabelValues(c.Name(), "miss").Inc()
+	}
+
+	res, err := c.next.Enrich(ctx, q)
+	if err != nil {
+		return Result{}, err
+	}
+	c.set(ctx, key, res)
+	return res, nil
+}
+
+func (c *Cache) key(q Query) string {
+	key := cacheKeyPrefix + ":" + c.Name() + ":"
+	if q.CountryID != "" {
+		key += strings.ToLower(q.CountryID) + ":"
+	}
+	return key + NormalizeName(q.Name)
+}
+
+func (c *Cache) get(ctx context.Context, key string) (Result, bool) {
+	raw, err := c.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("enrichment cache read failed", slog.String("key", key), slog.String("error", err.Error()))
+		}
+		return Result{}, false
+	}
+	var res Result
+	if err := json.Unmarshal(raw, &res); err != nil {
+		slog.Warn("enrichment cache entry is corrupted", slog.String("key", key), slog.String("error", err.Error()))
+		return Result{}, false
+	}
+	for i := range res.Predictions {
+		res.Predictions[i].Cached = true
+	}
+	return res, true
+}
+
+func (c *Cache) set(ctx context.Context, key string, res Result) {
+	raw, err := json.Marshal(res)
+	if err != nil {
+		slog.Warn("enrichment cache encode failed", slog.String("key", key), slog.String("error", err.Error()))
+		return
+	}
+	if err := c.rdb.Set(ctx, key, raw, c.ttl).Err(); err != nil {
+		slog.Warn("enrichment cache write failed", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,70 @@
+package enrich_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+// chain mirrors the remote pipeline of the app without the cache and quota:
+// normalizer -> circuit breaker -> retry -> batcher -> provider.
+type chain struct {
+	retry   enrich.RetryPolicy
+	breaker enrich.BreakerSettings
+	batch   time.Duration
+}
+
+func (c chain) wrap(p enrich.BatchEnricher) enrich.Enricher {
+	var e enrich.Enricher = p
+	if c.batch > 0 {
+		e = enrich.NewBatcher(p, c.batch, enrich.MaxBatchSize)
+	}
+	e = enrich.NewRetry(e, c.retry)
+	e = enrich.NewBreaker(e, c.breaker)
+	return enrich.NewNormalizer(e, enrich.TranslitGOST)
+}
+
+var defaultChain = chain{
+	retry:   enrich.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second},
+	breaker: enrich.BreakerSettings{FailureThreshold: 5, OpenTimeout: time.Minute},
+}
+
+func startServer(t *testing.T, faults enrichtest.Faults) (string, *enrichtest.Server) {
+	t.Helper()
+	fixtures, err := enrichtest.LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, srv := enrichtest.NewTestServer(fixtures, faults)
+	t.Cleanup(ts.Close)
+	return ts.URL, srv
+}
+
+func genderize(c chain, baseURL string) enrich.Enricher {
+	return c.wrap(enrich.NewGenderize(http.DefaultClient, enrichtest.Endpoint(baseURL, "genderize")))
+}
+
+func TestChainEnrichesCyrillicName(t *testing.T) {
+	baseURL, _ := startServer(t, enrichtest.Faults{})
+	registry := enrich.NewRegistry(
+		defaultChain.wrap(enrich.NewNationalize(http.DefaultClient, enrichtest.Endpoint(baseURL, "nationalize"))),
+	)
+	registry.RegisterLocalized(genderize(defaultChain, baseURL))
+	registry.RegisterLocalized(defaultChain.wrap(enrich.NewAgify(http.DefaultClient, enrichtest.Endpoint(baseURL, "agify"))))
+
+	res, err := registry.Enrich(context.Background(), enrich.Query{Name: "Дмитрий"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Gender != gender.GenderMale || res.Age == nil || *res.Age != 36 || res.Nationality != "RU" {
+		t.Errorf("got gender %q, age %v, nationality %q; want male, 36, RU", res.Gender, res.Age, res.Nationality)
+	}
+	if missing := res.Missing(); len(missing) != 0 {
+		t.Errorf("missing fields %v", missing)
+	}
+}
@@ -0,0 +1,172 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// Endpoint is where a provider is reached. APIKey is only needed for the
+// paid plans and is sent as the apikey parameter.
+type Endpoint struct {
+	BaseURL string
+	APIKey  string
+}
+
+// url adds the names to the base URL as query parameters: name for a single
+// name and name[] for a batch, as genderize, agify and nationalize expect.
+// An empty countryID is omitted.
+func (e Endpoint) url(countryID string, names ...string) string {
+	key := "name"
+	if len(names) > 1 {
+		key = "name[]"
+	}
+	params := url.Values{key: names}
+	if countryID != "" {
+		params.Set("country_id", countryID)
+	}
+	if e.APIKey != "" {
+		params.Set("apikey", e.APIKey)
+	}
+	return e.BaseURL + "?" + params.Encode()
+}
+
+// getJSON performs a GET request to rawURL and decodes the JSON body into dst.
+func getJSON(ctx context.Context, client *http.Client, provider, rawURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// keep the API key out of the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactAPIKey(urlErr.URL)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		slog.Error("ошибка получения данных", slog.String("provider", provider), slog.String("status", resp.Status))
+		return newStatusError(provider, resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		slog.Error("ошибка парсинга", slog.String("provider", provider), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func redactAPIKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	params := u.Query()
+	if params.Has("apikey") {
+		params.Set("apikey", "redacted")
+		u.RawQuery = params.Encode()
+	}
+	return u.String()
+}
+
+// LimitTransport lets at most n requests through next at the same time, a
+// request holds its slot until the response body is closed. A non-positive n
+// means no limit.
+func LimitTransport(n int, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if n <= 0 {
+		return next
+	}
+	return &limitTransport{slots: make(chan struct{}, n), next: next}
+}
+
+type limitTransport struct {
+	slots chan struct{}
+	next  http.RoundTripper
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		<-t.slots
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-t.slots }}
+	return resp, nil
+}
+
+// releaseBody frees the slot of a request once its body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// validateResponse checks a single decoded provider answer.
+func validateResponse(provider string, data any) error {
+	if err := validate.Struct(data); err != nil {
+		slog.Error("ошибка валидации данных", slog.String("provider", provider), slog.String("error", err.Error()))
+		return fmt.Errorf("validation error: %w", err)
+	}
+	return nil
+}
+
+// enrichBatch answers qs with a single name[] request and converts every
+// item with convert. An item that fails conversion is left empty. All the
+// queries must share the same CountryID.
+func enrichBatch[T any](
+	ctx context.Context,
+	client *http.Client,
+	provider string,
+	endpoint Endpoint,
+	qs []Query,
+	convert func(Query, T) (Result, error),
+) ([]Result, error) {
+	names := make([]string, len(qs))
+	for i, q := range qs {
+		names[i] = q.Name
+	}
+	var data []T
+	if err := getJSON(ctx, client, provider, endpoint.url(qs[0].CountryID, names...), &data); err != nil {
+		return nil, err
+	}
+	if len(data) != len(qs) {
+		return nil, fmt.Errorf("%s: expected %d batch items, got %d", provider, len(qs), len(data))
+	}
+
+	results := make([]Result, len(qs))
+	for i, q := range qs {
+		res, err := convert(q, data[i])
+		if err != nil {
+			continue
+		}
+		results[i] = res
+	}
+	return results, nil
+}
@@ -0,0 +1,275 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+// Query describes the person an Enricher is asked about.
+type Query struct {
+	Name       string
+	Surname    string
+	Patronymic string
+	// CountryID is an ISO 3166-1 alpha-2 code that localizes the prediction
+	// for providers supporting it.
+	CountryID string
+}
+
+// LocalizationMode tells which country, if any, age and gender were predicted for.
+type LocalizationMode string
+
+const (
+	// ModeGlobal predictions are made without a country.
+	ModeGlobal LocalizationMode = "global"
+	// ModeHint predictions use the country given by the caller.
+	ModeHint LocalizationMode = "hint"
+	// ModePredicted predictions use the nationality predicted for the name.
+	ModePredicted LocalizationMode = "predicted"
+)
+
+// Field names a value an Enricher can predict.
+type Field string
+
+const (
+	FieldGender      Field = "gender"
+	FieldAge         Field = "age"
+	FieldNationality Field = "nationality"
+)
+
+// Result holds the values predicted for a Query. Every Enricher fills only
+// the fields it knows about and leaves the rest zero-valued, including the
+// fields the provider has no data for.
+type Result struct {
+	Gender      gender.Gender    `json:"gender,omitempty"`
+	Age         *int             `json:"age,omitempty"`
+	Nationality string           `json:"nationality,omitempty"`
+	Mode        LocalizationMode `json:"mode,omitempty"`
+	// Sources names the provider each filled field came from.
+	Sources map[Field]string `json:"sources,omitempty"`
+	// Predictions keeps the raw answers the values above were derived from.
+	Predictions []Prediction `json:"predictions,omitempty"`
+}
+
+// Prediction is the answer of a single provider with its confidence.
+type Prediction struct {
+	Provider string `json:"provider"`
+	// Name is the name the provider was asked about.
+	Name string `json:"name"`
+	// CountryID is the country the prediction was localized for, empty if global.
+	CountryID string `json:"country_id,omitempty"`
+	// Value is the predicted gender, age or top country as text.
+	Value string `json:"value"`
+	// Probability is nil for providers that do not report one (agify).
+	Probability *float64            `json:"probability,omitempty"`
+	Count       int                 `json:"count"`
+	Countries   []CountryPrediction `json:"countries,omitempty"`
+	FetchedAt   time.Time           `json:"fetched_at"`
+	// Cached is set when the answer was served from the cache.
+	Cached bool `json:"cached,omitempty"`
+}
+
+// Missing lists the fields that could not be enriched.
+func (r Result) Missing() []Field {
+	var missing []Field
+	if r.Gender == "" || r.Gender == gender.Unknown {
+		missing = append(missing, FieldGender)
+	}
+	if r.Age == nil {
+		missing = append(missing, FieldAge)
+	}
+	if r.Nationality == "" {
+		missing = append(missing, FieldNationality)
+	}
+	return missing
+}
+
+// clone returns a copy of r that does not share its sources or predictions.
+func (r Result) clone() Result {
+	r.Sources = maps.Clone(r.Sources)
+	r.Predictions = slices.Clone(r.Predictions)
+	return r
+}
+
+// empty reports whether the result carries no values at all.
+func (r Result) empty() bool {
+	return r.Gender == "" && r.Age == nil && r.Nationality == ""
+}
+
+func (r *Result) setSource(field Field, source string) {
+	if source == "" {
+		return
+	}
+	if r.Sources == nil {
+		r.Sources = make(map[Field]string)
+	}
+	r.Sources[field] = source
+}
+
+// merge copies into r every field that is still empty in r but set in other.
+func (r *Result) merge(other Result) {
+	if r.Gender == "" || (r.Gender == gender.Unknown && other.Gender != "") {
+		r.Gender = other.Gender
+		r.setSource(FieldGender, other.Sources[FieldGender])
+	}
+	if r.Age == nil {
+		r.Age = other.Age
+		r.setSource(FieldAge, other.Sources[FieldAge])
+	}
+	if r.Nationality == "" {
+		r.Nationality = other.Nationality
+		r.setSource(FieldNationality, other.Sources[FieldNationality])
+	}
+	if r.Mode == "" {
+		r.Mode = other.Mode
+	}
+	r.Predictions = append(r.Predictions, other.Predictions...)
+}
+
+// Enricher predicts personal data by name. Implementations must be safe for
+// concurrent use.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, q Query) (Result, error)
+}
+
+// Registry runs a set of enrichers concurrently and merges their results.
+// Enrichers registered first take precedence when several of them fill the
+// same field. A failing enricher only leaves its fields missing; Enrich
+// returns an error when every enricher failed.
+//
+// Localized enrichers receive the country of the query. Without a country
+// hint and with country prediction enabled they run after the others, in the
+// nationality those predicted. A localized enricher with no data for the
+// country is asked again without it.
+type Registry struct {
+	mu             sync.RWMutex
+	enrichers      []Enricher
+	localized      []Enricher
+	predictCountry bool
+}
+
+func NewRegistry(enrichers ...Enricher) *Registry {
+	return &Registry{enrichers: enrichers}
+}
+
+func (r *Registry) Register(e Enricher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enrichers = append(r.enrichers, e)
+}
+
+func (r *Registry) RegisterLocalized(e Enricher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.localized = append(r.localized, e)
+}
+
+func (r *Registry) SetCountryPrediction(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.predictCountry = enabled
+}
+
+func (r *Registry) Name() string {
+	return "registry"
+}
+
+type registryCall struct {
+	enricher Enricher
+	query    Query
+}
+
+func (r *Registry) Enrich(ctx context.Context, q Query) (Result, error) {
+	r.mu.RLock()
+	enrichers := slices.Clone(r.enrichers)
+	localized := slices.Clone(r.localized)
+	predictCountry := r.predictCountry
+	r.mu.RUnlock()
+
+	global := q
+	global.CountryID = ""
+
+	if q.CountryID != "" || !predictCountry || len(localized) == 0 {
+		mode := ModeGlobal
+		if q.CountryID != "" {
+			mode = ModeHint
+		}
+		res, err := r.run(ctx, append(calls(enrichers, global), calls(localized, q)...))
+		res.Mode = mode
+		return res, err
+	}
+
+	res, err := r.run(ctx, calls(enrichers, global))
+	local, mode := global, ModeGlobal
+	if res.Nationality != "" {
+		local.CountryID, mode = res.Nationality, ModePredicted
+	}
+	localRes, localErr := r.run(ctx, calls(localized, local))
+	if err != nil && localErr != nil {
+		return Result{}, errors.Join(err, localErr)
+	}
+	res.merge(localRes)
+	res.Mode = mode
+	return res, nil
+}
+
+func calls(enrichers []Enricher, q Query) []registryCall {
+	out := make([]registryCall, len(enrichers))
+	for i, e := range enrichers {
+		out[i] = registryCall{enricher: e, query: q}
+	}
+	return out
+}
+
+// run performs the calls concurrently and fails only when all of them failed.
+func (r *Registry) run(ctx context.Context, calls []registryCall) (Result, error) {
+	results := make([]Result, len(calls))
+	errs := make([]error, len(calls))
+
+	var wg sync.WaitGroup
+	for i, c := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.enricher.Enrich(ctx, c.query)
+			if err == nil && c.query.CountryID != "" && res.empty() {
+				global := c.query
+				global.CountryID = ""
+				res, err = c.enricher.Enrich(ctx, global)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", c.enricher.Name(), err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	var (
+		res    Result
+		failed int
+	)
+	for i, one := range results {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+		res.merge(one)
+	}
+	if err := errors.Join(errs...); err != nil {
+		if failed == len(calls) {
+			return Result{}, err
+		}
+		slog.Warn("часть провайдеров обогащения недоступна", slog.String("error", err.Error()))
+	}
+	return res, nil
+}
@@ -0,0 +1,70 @@
+package enrichtest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// Fixture is everything the fake providers answer for one name. A nil Age
+// and an empty Gender or Country mean the provider does not know the name.
+type Fixture struct {
+	Name        string                     `json:"name"`
+	Gender      gender.Gender              `json:"gender,omitempty"`
+	Probability float64                    `json:"probability"`
+	Count       int                        `json:"count"`
+	Age         *int                       `json:"age,omitempty"`
+	Country     []enrich.CountryPrediction `json:"country,omitempty"`
+}
+
+// Fixtures maps normalized names to their answers.
+type Fixtures map[string]Fixture
+
+// ParseFixtures reads a JSON array of fixtures.
+func ParseFixtures(data []byte) (Fixtures, error) {
+	var list []Fixture
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse fixtures: %w", err)
+	}
+	fixtures := make(Fixtures, len(list))
+	for _, f := range list {
+		name := enrich.NormalizeName(f.Name)
+		if name == "" {
+			return nil, fmt.Errorf("parse fixtures: fixture without a name")
+		}
+		if _, ok := fixtures[name]; ok {
+			return nil, fmt.Errorf("parse fixtures: duplicate name %q", f.Name)
+		}
+		fixtures[name] = f
+	}
+	return fixtures, nil
+}
+
+// LoadFixtures reads the fixtures at path, or the embedded defaults when path
+// is empty.
+func LoadFixtures(path string) (Fixtures, error) {
+	if path == "" {
+		return ParseFixtures(defaultFixtures)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFixtures(data)
+}
+
+// lookup returns the fixture of name, or an unknown one echoing the name.
+func (f Fixtures) lookup(name string) Fixture {
+	if fixture, ok := f[enrich.NormalizeName(name)]; ok {
+		fixture.Name = name
+		return fixture
+	}
+	return Fixture{Name: name}
+}
@@ -0,0 +1,211 @@
+// Package enrichtest serves genderize-, agify- and nationalize-compatible
+// endpoints from fixtures, so enrichment can run without network access.
+package enrichtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+)
+
+// Faults makes the fake providers misbehave. The Every counters apply to all
+// requests in arrival order, so a run with the same requests is reproducible;
+// zero disables a fault.
+type Faults struct {
+	// Latency delays every answer.
+	Latency time.Duration
+	// RateLimitEvery answers every n-th request with 429 and Retry-After.
+	RateLimitEvery int
+	// ServerErrorEvery answers every n-th request with 503.
+	ServerErrorEvery int
+	// MalformedEvery answers every n-th request with a truncated JSON body.
+	MalformedEvery int
+	// Quota is the number of names served per provider before every request
+	// is rate limited. The X-Rate-Limit-* headers are only sent when set.
+	Quota int
+}
+
+// quotaReset is the X-Rate-Limit-Reset sent with every answer, the fake
+// quota itself is never renewed.
+const quotaReset = time.Hour
+
+// Server is the fake provider. Answers echo the requested names and
+// country_id, the fixtures are the same for every country.
+type Server struct {
+	fixtures Fixtures
+	mux      *http.ServeMux
+
+	mu       sync.Mutex
+	faults   Faults
+	requests int
+	served   map[string]int
+}
+
+func NewServer(fixtures Fixtures, faults Faults) *Server {
+	s := &Server{
+		fixtures: fixtures,
+		mux:      http.NewServeMux(),
+		faults:   faults,
+		served:   make(map[string]int),
+	}
+	s.handle("genderize", func(name string, f Fixture) any {
+		return enrich.GenderizeResponse{Name: name, Gender: f.Gender, Probability: f.Probability, Count: f.Count}
+	})
+	s.handle("agify", func(name string, f Fixture) any {
+		return enrich.AgifyResponse{Name: name, Age: f.Age, Count: f.Count}
+	})
+	s.handle("nationalize", func(name string, f Fixture) any {
+		country := f.Country
+		if country == nil {
+			country = []enrich.CountryPrediction{}
+		}
+		return enrich.NationalizeResponse{Name: name, Count: f.Count, Country: country}
+	})
+	return s
+}
+
+// NewTestServer starts a Server on a local port, the caller closes it.
+func NewTestServer(fixtures Fixtures, faults Faults) (*httptest.Server, *Server) {
+	s := NewServer(fixtures, faults)
+	return httptest.NewServer(s), s
+}
+
+// Endpoint returns the endpoint of provider on a Server listening at baseURL.
+func Endpoint(baseURL, provider string) enrich.Endpoint {
+	return enrich.Endpoint{BaseURL: strings.TrimSuffix(baseURL, "/") + "/" + provider}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetFaults replaces the faults of the running server.
+func (s *Server) SetFaults(faults Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = faults
+}
+
+// Requests returns the number of requests received so far.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+type fault int
+
+const (
+	faultNone fault = iota
+	faultRateLimit
+	faultServerError
+	faultMalformed
+)
+
+func (s *Server) handle(provider string, answer func(name string, f Fixture) any) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		names, batch := params["name[]"]
+		if !batch {
+			names = params["name"]
+		}
+		if len(names) == 0 || (!batch && len(names) != 1) {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "Missing 'name' parameter"})
+			return
+		}
+
+		faults, f, remaining := s.next(provider, len(names))
+		if faults.Latency > 0 {
+			select {
+			case <-time.After(faults.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if faults.Quota > 0 {
+			w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(faults.Quota))
+			w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(max(remaining, 0)))
+			w.Header().Set("X-Rate-Limit-Reset", strconv.Itoa(int(quotaReset.Seconds())))
+		}
+
+		switch {
+		case f == faultRateLimit || (faults.Quota > 0 && remaining < 0):
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "Request limit reached"})
+			return
+		case f == faultServerError:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Service unavailable"})
+			return
+		case f == faultMalformed:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name": "`))
+			return
+		}
+
+		countryID := params.Get("country_id")
+		answers := make([]any, len(names))
+		for i, name := range names {
+			answers[i] = withCountry(answer(name, s.fixtures.lookup(name)), countryID)
+		}
+		if batch {
+			writeJSON(w, http.StatusOK, answers)
+			return
+		}
+		writeJSON(w, http.StatusOK, answers[0])
+	}
+	s.mux.HandleFunc("GET /"+provider, handler)
+	s.mux.HandleFunc("GET /"+provider+"/{$}", handler)
+}
+
+// next counts the request and picks its fault. remaining is the quota left
+// after the names of the request, negative when they did not fit.
+func (s *Server) next(provider string, names int) (Faults, fault, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	f := faultNone
+	switch n := s.requests; {
+	case every(n, s.faults.RateLimitEvery):
+		f = faultRateLimit
+	case every(n, s.faults.ServerErrorEvery):
+		f = faultServerError
+	case every(n, s.faults.MalformedEvery):
+		f = faultMalformed
+	}
+
+	remaining := s.faults.Quota - s.served[provider] - names
+	if f == faultNone && (s.faults.Quota == 0 || remaining >= 0) {
+		s.served[provider] += names
+	}
+	return s.faults, f, remaining
+}
+
+func every(n, period int) bool {
+	return period > 0 && n%period == 0
+}
+
+// withCountry adds the country_id field the real providers echo for
+// localized requests.
+func withCountry(answer any, countryID string) any {
+	if countryID == "" {
+		return answer
+	}
+	data, _ := json.Marshal(answer)
+	var fields map[string]any
+	_ = json.Unmarshal(data, &fields)
+	fields["country_id"] = countryID
+	return fields
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
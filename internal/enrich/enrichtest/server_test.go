@@ -0,0 +1,106 @@
+package enrichtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+)
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"defaults", string(defaultFixtures), false},
+		{"normalized name", `[{"name": "  Ivan "}]`, false},
+		{"no name", `[{"gender": "male"}]`, true},
+		{"duplicate after normalization", `[{"name": "ivan"}, {"name": "IVAN"}]`, true},
+		{"not an array", `{"name": "ivan"}`, true},
+	}
+	for _, tt := range tests {
+		_, err := ParseFixtures([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func get(t *testing.T, rawURL string, dst any) *http.Response {
+	t.Helper()
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if dst != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp
+}
+
+func TestServerAnswers(t *testing.T) {
+	fixtures, err := LoadFixtures("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, srv := NewTestServer(fixtures, Faults{})
+	defer ts.Close()
+
+	var single enrich.GenderizeResponse
+	get(t, ts.URL+"/genderize?name=Dmitrii", &single)
+	if single.Name != "Dmitrii" || single.Gender != "male" {
+		t.Errorf("single answer %+v, want the fixture echoing the name", single)
+	}
+
+	var batch []map[string]any
+	get(t, ts.URL+"/agify/?name[]=iuliia&name[]=nobody&country_id=RU", &batch)
+	if len(batch) != 2 || batch[0]["name"] != "iuliia" || batch[1]["name"] != "nobody" {
+		t.Fatalf("batch answer %v, want one answer per name in order", batch)
+	}
+	if batch[0]["age"] != float64(33) || batch[1]["age"] != nil {
+		t.Errorf("ages %v and %v, want 33 and null", batch[0]["age"], batch[1]["age"])
+	}
+	if batch[0]["country_id"] != "RU" {
+		t.Errorf("country_id %v, want RU echoed", batch[0]["country_id"])
+	}
+
+	if resp := get(t, ts.URL+"/nationalize", nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("missing name: status %d, want 422", resp.StatusCode)
+	}
+	if got := srv.Requests(); got != 2 {
+		t.Errorf("requests = %d, want 2: rejected requests are not counted", got)
+	}
+}
+
+func TestServerQuota(t *testing.T) {
+	ts, _ := NewTestServer(Fixtures{}, Faults{Quota: 3})
+	defer ts.Close()
+
+	names := url.Values{"name[]": {"a", "b"}}.Encode()
+	tests := []struct {
+		status    int
+		remaining string
+	}{
+		{http.StatusOK, "1"},
+		{http.StatusTooManyRequests, "0"},
+	}
+	for i, tt := range tests {
+		resp := get(t, ts.URL+"/genderize?"+names, nil)
+		if resp.StatusCode != tt.status || resp.Header.Get("X-Rate-Limit-Remaining") != tt.remaining {
+			t.Errorf("request %d: status %d, remaining %q; want %d, %q",
+				i+1, resp.StatusCode, resp.Header.Get("X-Rate-Limit-Remaining"), tt.status, tt.remaining)
+		}
+		if resp.Header.Get("X-Rate-Limit-Limit") != "3" || resp.Header.Get("X-Rate-Limit-Reset") == "" {
+			t.Errorf("request %d: quota headers %v", i+1, resp.Header)
+		}
+	}
+	if resp := get(t, ts.URL+"/agify?name=a", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("agify status %d, want its own quota", resp.StatusCode)
+	}
+}
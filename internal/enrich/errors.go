@@ -0,0 +1,69 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// StatusError is returned when a provider answers with a non-200 status.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	// RetryAfter is parsed from the Retry-After header, zero if absent.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code %d", e.Provider, e.StatusCode)
+}
+
+func newStatusError(provider string, resp *http.Response) *StatusError {
+	return &StatusError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter understands both forms of the header: delay in seconds and HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
+
+// isTransient reports whether err is a provider failure worth retrying:
+// a network error, a timeout, 429 or 5xx.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Unavailable reports whether err means the providers cannot be asked right
+// now: an open circuit, a spent quota or a transient failure. Other errors
+// are failed or unreadable answers.
+func Unavailable(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrQuotaExhausted) ||
+		errors.Is(err, context.DeadlineExceeded) || isTransient(err)
+}
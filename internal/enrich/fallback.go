@@ -0,0 +1,34 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+)
+
+// Fallback answers from fallback when primary fails, e.g. when the remote
+// provider is unreachable or its circuit is open.
+type Fallback struct {
+	primary  Enricher
+	fallback Enricher
+}
+
+func NewFallback(primary, fallback Enricher) *Fallback {
+	return &Fallback{primary: primary, fallback: fallback}
+}
+
+func (f *Fallback) Name() string {
+	return f.primary.Name()
+}
+
+func (f *Fallback) Enrich(ctx context.Context, q Query) (Result, error) {
+	res, err := f.primary.Enrich(ctx, q)
+	if err == nil || ctx.Err() != nil {
+		return res, err
+	}
+	fallbackRequests.WithLabelValues(f.primary.Name(), f.fallback.Name()).Inc()
+	res, fallbackErr := f.fallback.Enrich(ctx, q)
+	if fallbackErr != nil {
+		return Result{}, errors.Join(err, fallbackErr)
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+// GenderizeURL is the public endpoint used when no base URL is configured.
+const GenderizeURL = "https://api.genderize.io/"
+
+// GenderizeResponse has an empty Gender when the name is unknown to genderize.
+type GenderizeResponse struct {
+	Name        string        `json:"name" validate:"required"`
+	Gender      gender.Gender `json:"gender" validate:"omitempty,oneof=male female"`
+	Probability float64       `json:"probability" validate:"gte=0,lte=1"`
+	Count       int           `json:"count" validate:"gte=0"`
+}
+
+// Genderize predicts gender using https://genderize.io.
+type Genderize struct {
+	client   *http.Client
+	endpoint Endpoint
+}
+
+func NewGenderize(client *http.Client, endpoint Endpoint) *Genderize {
+	if endpoint.BaseURL == "" {
+		endpoint.BaseURL = GenderizeURL
+	}
+	return &Genderize{client: client, endpoint: endpoint}
+}
+
+func (g *Genderize) Name() string {
+	return "genderize"
+}
+
+func (g *Genderize) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data GenderizeResponse
+	if err := getJSON(ctx, g.client, g.Name(), g.endpoint.url(q.CountryID, q.Name), &data); err != nil {
+		return Result{}, err
+	}
+	return g.result(q, data)
+}
+
+func (g *Genderize) EnrichBatch(ctx context.Context, qs []Query) ([]Result, error) {
+	return enrichBatch(ctx, g.client, g.Name(), g.endpoint, qs, g.result)
+}
+
+func (g *Genderize) result(q Query, data GenderizeResponse) (Result, error) {
+	if err := validateResponse(g.Name(), &data); err != nil {
+		return Result{}, err
+	}
+	res := Result{
+		Gender: data.Gender,
+		Predictions: []Prediction{{
+			Provider:    g.Name(),
+			Name:        q.Name,
+			CountryID:   q.CountryID,
+			Value:       string(data.Gender),
+			Probability: &data.Probability,
+			Count:       data.Count,
+			FetchedAt:   time.Now().UTC(),
+		}},
+	}
+	if data.Gender != "" {
+		res.setSource(FieldGender, g.Name())
+	}
+	return res, nil
+}
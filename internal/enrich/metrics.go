@@ -0,0 +1,95 @@
+package enrich
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func init() {
+	prometheus.MustRegister(cacheRequests)
+	prometheus.MustRegister(providerRetries)
+	prometheus.MustRegister(circuitState)
+	prometheus.MustRegister(batchSize)
+	prometheus.MustRegister(fallbackRequests)
+	prometheus.MustRegister(quotaRemaining)
+	prometheus.MustRegister(quotaRejections)
+	prometheus.MustRegister(lowConfidence)
+}
+
+var cacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Enrichment cache lookups by provider and outcome (hit, miss, bypass).",
+	},
+	[]string{"provider", "result"},
+)
+
+var providerRetries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "retries_total",
+		Help:      "Retried enrichment provider calls.",
+	},
+	[]string{"provider"},
+)
+
+var circuitState = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "circuit_state",
+		Help:      "Circuit breaker state per provider: 0 closed, 1 half-open, 2 open.",
+	},
+	[]string{"provider"},
+)
+
+var batchSize = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "batch_size",
+		Help:      "Number of names sent to a provider in one request.",
+		Buckets:   prometheus.LinearBuckets(1, 1, MaxBatchSize),
+	},
+	[]string{"provider"},
+)
+
+var fallbackRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "fallback_total",
+		Help:      "Lookups answered by the fallback enricher after the primary one failed.",
+	},
+	[]string{"provider", "fallback"},
+)
+
+var quotaRemaining = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "quota_remaining",
+		Help:      "Requests left in the provider budget as reported by X-Rate-Limit-Remaining.",
+	},
+	[]string{"provider"},
+)
+
+var quotaRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "quota_rejections_total",
+		Help:      "Lookups refused because the provider budget reached the reserve.",
+	},
+	[]string{"provider"},
+)
+
+var lowConfidence = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "provider",
+		Name:      "low_confidence_total",
+		Help:      "Predicted values dropped for a probability or sample count below the thresholds.",
+	},
+	[]string{"provider"},
+)
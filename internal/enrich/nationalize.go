@@ -0,0 +1,77 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// NationalizeURL is the public endpoint used when no base URL is configured.
+const NationalizeURL = "https://api.nationalize.io/"
+
+// NationalizeResponse has no countries when the name is unknown to nationalize.
+type NationalizeResponse struct {
+	Name    string              `json:"name"`
+	Count   int                 `json:"count"`
+	Country []CountryPrediction `json:"country"`
+}
+
+type CountryPrediction struct {
+	CountryID   string  `json:"country_id"`
+	Probability float64 `json:"probability"`
+}
+
+// Nationalize predicts nationality using https://nationalize.io.
+type Nationalize struct {
+	client   *http.Client
+	endpoint Endpoint
+}
+
+func NewNationalize(client *http.Client, endpoint Endpoint) *Nationalize {
+	if endpoint.BaseURL == "" {
+		endpoint.BaseURL = NationalizeURL
+	}
+	return &Nationalize{client: client, endpoint: endpoint}
+}
+
+func (n *Nationalize) Name() string {
+	return "nationalize"
+}
+
+func (n *Nationalize) Enrich(ctx context.Context, q Query) (Result, error) {
+	var data NationalizeResponse
+	if err := getJSON(ctx, n.client, n.Name(), n.endpoint.url("", q.Name), &data); err != nil {
+		return Result{}, err
+	}
+	return n.result(q, data)
+}
+
+func (n *Nationalize) EnrichBatch(ctx context.Context, qs []Query) ([]Result, error) {
+	return enrichBatch(ctx, n.client, n.Name(), n.endpoint, qs, n.result)
+}
+
+func (n *Nationalize) result(q Query, data NationalizeResponse) (Result, error) {
+	if err := validateResponse(n.Name(), &data); err != nil {
+		return Result{}, err
+	}
+	prediction := Prediction{
+		Provider:  n.Name(),
+		Name:      q.Name,
+		Count:     data.Count,
+		Countries: data.Country,
+		FetchedAt: time.Now().UTC(),
+	}
+	if len(data.Country) == 0 {
+		return Result{Predictions: []Prediction{prediction}}, nil
+	}
+
+	top := data.Country[0]
+	prediction.Value = top.CountryID
+	prediction.Probability = &top.Probability
+	res := Result{
+		Nationality: top.CountryID,
+		Predictions: []Prediction{prediction},
+	}
+	res.setSource(FieldNationality, n.Name())
+	return res, nil
+}
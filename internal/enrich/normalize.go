@@ -0,0 +1,96 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliteration selects how Cyrillic names are spelled in Latin before they
+// are sent to the providers.
+type Transliteration string
+
+const (
+	// TranslitGOST follows GOST R 52535.1-2006, the scheme used in Russian
+	// passports (Юлия -> iuliia). Its output is plain ASCII.
+	TranslitGOST Transliteration = "gost"
+	// TranslitISO9 follows ISO 9:1995, one Latin letter with diacritics per
+	// Cyrillic letter (Юлия -> ûliâ).
+	TranslitISO9 Transliteration = "iso9"
+	// TranslitOff sends the names as they are.
+	TranslitOff Transliteration = "off"
+)
+
+var transliterations = map[Transliteration]map[rune]string{
+	TranslitGOST: {
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "tc", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+	},
+	TranslitISO9: {
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "ë",
+		'ж': "ž", 'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'ш': "š", 'щ': "ŝ",
+		'ъ': "ʺ", 'ы': "y", 'ь': "ʹ", 'э': "è", 'ю': "û", 'я': "â",
+	},
+}
+
+// ParseTransliteration checks that s names a known scheme.
+func ParseTransliteration(s string) (Transliteration, error) {
+	t := Transliteration(s)
+	if _, ok := transliterations[t]; !ok && t != TranslitOff {
+		return "", fmt.Errorf("unknown transliteration %q", s)
+	}
+	return t, nil
+}
+
+// NormalizeName turns a first name into the form used for lookups and cache
+// keys: Unicode NFC, trimmed, lower-cased and with inner whitespace collapsed.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(name)), " "))
+}
+
+// Transliterate normalizes name and spells its Cyrillic letters in Latin
+// using scheme. Other characters are kept.
+func Transliterate(name string, scheme Transliteration) string {
+	name = NormalizeName(name)
+	table, ok := transliterations[scheme]
+	if !ok {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		if latin, ok := table[r]; ok {
+			b.WriteString(latin)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Normalizer cleans up and transliterates the first name before passing the
+// query to next, so Cyrillic names are looked up by their Latin spelling.
+// The caller keeps the original spelling.
+type Normalizer struct {
+	next   Enricher
+	scheme Transliteration
+}
+
+func NewNormalizer(next Enricher, scheme Transliteration) *Normalizer {
+	return &Normalizer{next: next, scheme: scheme}
+}
+
+func (n *Normalizer) Name() string {
+	return n.next.Name()
+}
+
+func (n *Normalizer) Enrich(ctx context.Context, q Query) (Result, error) {
+	q.Name = Transliterate(q.Name, n.scheme)
+	return n.next.Enrich(ctx, q)
+}
@@ -0,0 +1,28 @@
+package enrich
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme Transliteration
+		want   string
+	}{
+		{"Юлия", TranslitGOST, "iuliia"},
+		{"Юлия", TranslitISO9, "ûliâ"},
+		{"Юлия", TranslitOff, "юлия"},
+		{"Дмитрий", TranslitGOST, "dmitrii"},
+		{"Дмитрий", TranslitISO9, "dmitrij"},
+		{"Артём", TranslitGOST, "artem"},
+		{"Щукин", TranslitGOST, "shchukin"},
+		{"Цветкова", TranslitISO9, "cvetkova"},
+		{"  Анна   Мария ", TranslitGOST, "anna mariia"},
+		{"Ivan", TranslitGOST, "ivan"},
+		{"Ян-Ivan", TranslitGOST, "ian-ivan"},
+	}
+	for _, tt := range tests {
+		if got := Transliterate(tt.name, tt.scheme); got != tt.want {
+			t.Errorf("Transliterate(%q, %s) = %q, want %q", tt.name, tt.scheme, got, tt.want)
+		}
+	}
+}
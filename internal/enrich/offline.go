@@ -0,0 +1,104 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+//go:embed names.csv
+var namesCSV []byte
+
+type dictionaryEntry struct {
+	gender  gender.Gender
+	age     int
+	country string
+}
+
+// Dictionary is an embedded dataset of common first names with their usual
+// gender, typical age and country. It needs no network access.
+type Dictionary struct {
+	entries map[string]dictionaryEntry
+}
+
+// LoadDictionary keys the names by their spelling in scheme, the one a
+// Normalizer in front of Offline looks them up with. A Latin name listed
+// next to a Cyrillic one that transliterates to it wins.
+func LoadDictionary(scheme Transliteration) (*Dictionary, error) {
+	records, err := csv.NewReader(bytes.NewReader(namesCSV)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read names dictionary: %w", err)
+	}
+	entries := make(map[string]dictionaryEntry, len(records))
+	for i, record := range records {
+		if i == 0 {
+			continue // header
+		}
+		if len(record) != 4 {
+			return nil, fmt.Errorf("names dictionary line %d: expected 4 columns, got %d", i+1, len(record))
+		}
+		age, err := strconv.Atoi(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("names dictionary line %d: %w", i+1, err)
+		}
+		key := Transliterate(record[0], scheme)
+		if _, ok := entries[key]; ok {
+			continue
+		}
+		entries[key] = dictionaryEntry{
+			gender:  gender.Gender(record[1]),
+			age:     age,
+			country: record[3],
+		}
+	}
+	return &Dictionary{entries: entries}, nil
+}
+
+// Offline predicts one field from the Dictionary. Country hints are ignored.
+type Offline struct {
+	dict  *Dictionary
+	field Field
+}
+
+func NewOffline(dict *Dictionary, field Field) *Offline {
+	return &Offline{dict: dict, field: field}
+}
+
+func (o *Offline) Name() string {
+	return "offline_" + string(o.field)
+}
+
+func (o *Offline) Enrich(_ context.Context, q Query) (Result, error) {
+	prediction := Prediction{
+		Provider:  o.Name(),
+		Name:      q.Name,
+		FetchedAt: time.Now().UTC(),
+	}
+	entry, ok := o.dict.entries[NormalizeName(q.Name)]
+	if !ok {
+		return Result{Predictions: []Prediction{prediction}}, nil
+	}
+
+	var res Result
+	switch o.field {
+	case FieldGender:
+		res.Gender = entry.gender
+		prediction.Value = string(entry.gender)
+	case FieldAge:
+		age := entry.age
+		res.Age = &age
+		prediction.Value = strconv.Itoa(age)
+	case FieldNationality:
+		res.Nationality = entry.country
+		prediction.Value = entry.country
+	}
+	res.Predictions = []Prediction{prediction}
+	res.setSource(o.field, o.Name())
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package enrich
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+func TestOfflineResolvesCyrillicNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		gender      gender.Gender
+		nationality string
+	}{
+		{"Дмитрий", gender.GenderMale, "RU"},
+		{"Сергей", gender.GenderMale, "RU"},
+		{"Андрей", gender.GenderMale, "RU"},
+		{"Николай", gender.GenderMale, "RU"},
+		{"Юрий", gender.GenderMale, "RU"},
+		{"Юлия", gender.GenderFemale, "RU"},
+		{"Мария", gender.GenderFemale, "RU"},
+		{"Артём", gender.GenderMale, "RU"},
+		{"Dmitry", gender.GenderMale, "RU"},
+	}
+	for _, scheme := range []Transliteration{TranslitGOST, TranslitISO9, TranslitOff} {
+		dict, err := LoadDictionary(scheme)
+		if err != nil {
+			t.Fatalf("LoadDictionary(%s): %v", scheme, err)
+		}
+		genders := NewNormalizer(NewOffline(dict, FieldGender), scheme)
+		nationalities := NewNormalizer(NewOffline(dict, FieldNationality), scheme)
+		for _, tt := range tests {
+			res, err := genders.Enrich(context.Background(), Query{Name: tt.name})
+			if err != nil {
+				t.Fatalf("%s %s: %v", scheme, tt.name, err)
+			}
+			if res.Gender != tt.gender {
+				t.Errorf("%s %s: gender = %q, want %q", scheme, tt.name, res.Gender, tt.gender)
+			}
+			res, err = nationalities.Enrich(context.Background(), Query{Name: tt.name})
+			if err != nil {
+				t.Fatalf("%s %s: %v", scheme, tt.name, err)
+			}
+			if res.Nationality != tt.nationality {
+				t.Errorf("%s %s: nationality = %q, want %q", scheme, tt.name, res.Nationality, tt.nationality)
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrQuotaExhausted = errors.New("provider quota is exhausted")
+
+const quotaKeyPrefix = cacheKeyPrefix + ":quota:"
+
+// QuotaBudget is the request budget a provider reported in its last answer.
+type QuotaBudget struct {
+	Provider  string    `json:"provider"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// Quota tracks the X-Rate-Limit-* headers of the providers in Redis, so the
+// budget is shared by all instances of the service. A budget disappears when
+// its reset time passes. Redis failures are logged and never fail a lookup.
+type Quota struct {
+	rdb       *redis.Client
+	reserve   int
+	providers []string
+}
+
+func NewQuota(rdb *redis.Client, reserve int) *Quota {
+	return &Quota{rdb: rdb, reserve: reserve}
+}
+
+// Transport returns a RoundTripper recording the budget reported in the
+// responses of provider.
+func (q *Quota) Transport(provider string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	q.providers = append(q.providers, provider)
+	return &quotaTransport{quota: q, provider: provider, next: next}
+}
+
+// Guard returns an Enricher refusing lookups while the budget of next is
+// within the reserve.
+func (q *Quota) Guard(next Enricher) Enricher {
+	return &quotaGuard{quota: q, next: next}
+}
+
+// Budgets returns the known budgets of the providers with a transport.
+// A nil Quota, used when tracking is disabled, has no budgets.
+func (q *Quota) Budgets(ctx context.Context) ([]QuotaBudget, error) {
+	if q == nil {
+		return []QuotaBudget{}, nil
+	}
+	budgets := make([]QuotaBudget, 0, len(q.providers))
+	for _, provider := range q.providers {
+		budget, ok, err := q.budget(ctx, provider)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets, nil
+}
+
+func (q *Quota) budget(ctx context.Context, provider string) (QuotaBudget, bool, error) {
+	values, err := q.rdb.HGetAll(ctx, quotaKeyPrefix+provider).Result()
+	if err != nil || len(values) == 0 {
+		return QuotaBudget{}, false, err
+	}
+	budget := QuotaBudget{Provider: provider}
+	budget.Limit, _ = strconv.Atoi(values["limit"])
+	budget.Remaining, _ = strconv.Atoi(values["remaining"])
+	resetAt, _ := strconv.ParseInt(values["reset_at"], 10, 64)
+	budget.ResetAt = time.Unix(resetAt, 0).UTC()
+	return budget, true, nil
+}
+
+func (q *Quota) record(ctx context.Context, budget QuotaBudget) {
+	quotaRemaining.WithLabelValues(budget.Provider).Set(float64(budget.Remaining))
+
+	key := quotaKeyPrefix + budget.Provider
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"limit", budget.Limit,
+			"remaining", budget.Remaining,
+			"reset_at", budget.ResetAt.Unix())
+		pipe.ExpireAt(ctx, key, budget.ResetAt)
+		return nil
+	})
+	if err != nil {
+		slog.Warn("ошибка записи квоты провайдера", slog.String("provider", budget.Provider), slog.String("error", err.Error()))
+	}
+}
+
+type quotaTransport struct {
+	quota    *Quota
+	provider string
+	next     http.RoundTripper
+}
+
+func (t *quotaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if budget, ok := parseQuota(t.provider, resp.Header, time.Now()); ok {
+		t.quota.record(req.Context(), budget)
+	}
+	return resp, nil
+}
+
+// parseQuota reads the budget headers of genderize, agify and nationalize.
+// X-Rate-Limit-Reset is the number of seconds until the budget is renewed.
+func parseQuota(provider string, header http.Header, now time.Time) (QuotaBudget, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-Rate-Limit-Remaining"))
+	if err != nil {
+		return QuotaBudget{}, false
+	}
+	reset, err := strconv.Atoi(header.Get("X-Rate-Limit-Reset"))
+	if err != nil {
+		return QuotaBudget{}, false
+	}
+	limit, _ := strconv.Atoi(header.Get("X-Rate-Limit-Limit"))
+	return QuotaBudget{
+		Provider:  provider,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   now.Add(time.Duration(max(reset, 1)) * time.Second),
+	}, true
+}
+
+type quotaGuard struct {
+	quota *Quota
+	next  Enricher
+}
+
+func (g *quotaGuard) Name() string {
+	return g.next.Name()
+}
+
+func (g *quotaGuard) Enrich(ctx context.Context, q Query) (Result, error) {
+	budget, ok, err := g.quota.budget(ctx, g.Name())
+	if err != nil {
+		slog.Warn("ошибка чтения квоты провайдера", slog.String("provider", g.Name()), slog.String("error", err.Error()))
+	}
+	if ok && budget.Remaining <= g.quota.reserve && time.Now().Before(budget.ResetAt) {
+		quotaRejections.WithLabelValues(g.Name()).Inc()
+		return Result{}, ErrQuotaExhausted
+	}
+	return g.next.Enrich(ctx, q)
+}
@@ -0,0 +1,45 @@
+package enrich
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseQuota(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	header := func(limit, remaining, reset string) http.Header {
+		h := make(http.Header)
+		for key, value := range map[string]string{
+			"X-Rate-Limit-Limit":     limit,
+			"X-Rate-Limit-Remaining": remaining,
+			"X-Rate-Limit-Reset":     reset,
+		} {
+			if value != "" {
+				h.Set(key, value)
+			}
+		}
+		return h
+	}
+	tests := []struct {
+		name   string
+		header http.Header
+		want   QuotaBudget
+		ok     bool
+	}{
+		{"all headers", header("1000", "42", "3600"), QuotaBudget{Provider: "agify", Limit: 1000, Remaining: 42, ResetAt: now.Add(time.Hour)}, true},
+		{"no headers", header("", "", ""), QuotaBudget{}, false},
+		{"no remaining", header("1000", "", "3600"), QuotaBudget{}, false},
+		{"no reset", header("1000", "42", ""), QuotaBudget{}, false},
+		{"garbage remaining", header("1000", "many", "3600"), QuotaBudget{}, false},
+		{"no limit", header("", "0", "60"), QuotaBudget{Provider: "agify", Remaining: 0, ResetAt: now.Add(time.Minute)}, true},
+		{"reset now is clamped", header("1000", "0", "0"), QuotaBudget{Provider: "agify", Limit: 1000, ResetAt: now.Add(time.Second)}, true},
+		{"negative reset is clamped", header("1000", "0", "-5"), QuotaBudget{Provider: "agify", Limit: 1000, ResetAt: now.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseQuota("agify", tt.header, now)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: got %+v, %v; want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// backoff returns the full-jitter exponential delay before the given retry (1-based).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay << (retry - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// Retry repeats transient provider failures with jittered exponential backoff.
+// A Retry-After longer than MaxDelay is not waited for and fails immediately.
+type Retry struct {
+	next   Enricher
+	policy RetryPolicy
+}
+
+func NewRetry(next Enricher, policy RetryPolicy) *Retry {
+	return &Retry{next: next, policy: policy}
+}
+
+func (r *Retry) Name() string {
+	return r.next.Name()
+}
+
+func (r *Retry) Enrich(ctx context.Context, q Query) (Result, error) {
+	var (
+		res Result
+		err error
+	)
+	for attempt := 1; ; attempt++ {
+		res, err = r.next.Enrich(ctx, q)
+		if err == nil || !isTransient(err) || attempt >= r.policy.MaxAttempts {
+			return res, err
+		}
+
+		delay := r.policy.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > r.policy.MaxDelay {
+				return res, err
+			}
+			delay = statusErr.RetryAfter
+		}
+
+		slog.Warn("повторный запрос к провайдеру обогащения",
+			slog.String("provider", r.Name()),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()))
+		providerRetries.WithLabelValues(r.Name()).Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
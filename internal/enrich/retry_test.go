@@ -0,0 +1,77 @@
+package enrich_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+func TestRetryRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDelay time.Duration
+		wantErr  bool
+		requests int
+	}{
+		{"retry after within max delay", 2 * time.Second, false, 3},
+		{"retry after beyond max delay", 500 * time.Millisecond, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, srv := startServer(t, enrichtest.Faults{RateLimitEvery: 2})
+			c := defaultChain
+			c.retry.MaxDelay = tt.maxDelay
+			e := genderize(c, baseURL)
+
+			if _, err := e.Enrich(context.Background(), enrich.Query{Name: "Ivan"}); err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			res, err := e.Enrich(context.Background(), enrich.Query{Name: "Ivan"})
+			if tt.wantErr {
+				var statusErr *enrich.StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+					t.Fatalf("got %v, want a 429 status error", err)
+				}
+				if !enrich.Unavailable(err) {
+					t.Errorf("429 is not reported as unavailable")
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Gender != gender.GenderMale {
+					t.Errorf("gender = %q, want male", res.Gender)
+				}
+				if elapsed := time.Since(start); elapsed < time.Second {
+					t.Errorf("retried after %s, want the 1s of Retry-After", elapsed)
+				}
+			}
+			if got := srv.Requests(); got != tt.requests {
+				t.Errorf("requests = %d, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetrySkipsMalformedAnswer(t *testing.T) {
+	baseURL, srv := startServer(t, enrichtest.Faults{MalformedEvery: 1})
+	e := genderize(defaultChain, baseURL)
+
+	_, err := e.Enrich(context.Background(), enrich.Query{Name: "Ivan"})
+	if err == nil {
+		t.Fatal("malformed answer accepted")
+	}
+	if enrich.Unavailable(err) {
+		t.Errorf("malformed answer reported as unavailable: %v", err)
+	}
+	if got := srv.Requests(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
@@ -0,0 +1,144 @@
+package enrich
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+// Sources recorded for a gender inferred by GenderRules.
+const (
+	SourcePatronymic = "patronymic"
+	SourceSurname    = "surname"
+)
+
+// genderEndings lists the suffixes of one name part. Feminine suffixes are
+// checked first since many of them extend a masculine one (-ова and -ов).
+type genderEndings struct {
+	female []string
+	male   []string
+}
+
+var (
+	patronymicEndings = genderEndings{
+		female: []string{
+			"овна", "евна", "ична", "кызы",
+			"ovna", "evna", "ichna", "kyzy", "kizi",
+		},
+		male: []string{
+			"ович", "евич", "ич", "оглы", "улы",
+			"ovich", "evich", "ich", "ogly", "oglu", "uly",
+		},
+	}
+	surnameEndings = genderEndings{
+		female: []string{
+			"ова", "ева", "ёва", "ина", "ына", "ская", "цкая",
+			"ova", "eva", "yova", "ina", "yna", "skaya", "tskaya",
+		},
+		male: []string{
+			"ов", "ев", "ёв", "ин", "ын", "ский", "цкий", "ской",
+			"ov", "ev", "yov", "in", "yn", "sky", "skiy", "skii", "skoy", "tsky", "tskiy", "tskii",
+		},
+	}
+)
+
+// surnameCountries are the countries whose transliterated surnames follow the
+// Russian endings. Elsewhere -ina or -in say nothing (Medina, Martin).
+var surnameCountries = map[string]bool{"RU": true, "UA": true, "BY": true, "KZ": true}
+
+// GenderRules infers gender from Russian patronymic and surname endings
+// before asking next. The patronymic is trusted over the surname. Latin
+// surnames are only judged for the countries in surnameCountries. With a nil
+// next the rules are used instead of a provider and an undetermined gender is
+// left empty.
+type GenderRules struct {
+	next Enricher
+}
+
+func NewGenderRules(next Enricher) *GenderRules {
+	return &GenderRules{next: next}
+}
+
+func (g *GenderRules) Name() string {
+	if g.next != nil {
+		return g.next.Name()
+	}
+	return "gender_rules"
+}
+
+func (g *GenderRules) Enrich(ctx context.Context, q Query) (Result, error) {
+	for _, part := range []struct {
+		source string
+		value  string
+		rules  genderEndings
+	}{
+		{SourcePatronymic, q.Patronymic, patronymicEndings},
+		{SourceSurname, q.Surname, surnameEndings},
+	} {
+		value := NormalizeName(part.value)
+		if value == "" {
+			continue
+		}
+		if part.source == SourceSurname && !isCyrillic(value) && !surnameCountries[strings.ToUpper(q.CountryID)] {
+			continue
+		}
+		if gen, ok := matchEnding(value, part.rules); ok {
+			res := Result{
+				Gender: gen,
+				Predictions: []Prediction{{
+					Provider:  part.source,
+					Name:      value,
+					Value:     string(gen),
+					FetchedAt: time.Now().UTC(),
+				}},
+			}
+			res.setSource(FieldGender, part.source)
+			return res, nil
+		}
+	}
+	if g.next == nil {
+		return Result{}, nil
+	}
+	return g.next.Enrich(ctx, q)
+}
+
+func matchEnding(value string, rules genderEndings) (gender.Gender, bool) {
+	// compound names are decided by their last part, which may be a whole
+	// word such as "оглы"
+	compound := false
+	if i := strings.LastIndexAny(value, " -"); i >= 0 {
+		value, compound = value[i+1:], true
+	}
+	if hasEnding(value, rules.female, compound) {
+		return gender.GenderFemale, true
+	}
+	if hasEnding(value, rules.male, compound) {
+		return gender.GenderMale, true
+	}
+	return "", false
+}
+
+func isCyrillic(value string) bool {
+	for _, r := range value {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEnding(value string, suffixes []string, whole bool) bool {
+	for _, suffix := range suffixes {
+		if whole && value == suffix {
+			return true
+		}
+		// the stem must be longer than the ending itself
+		if strings.HasSuffix(value, suffix) && len([]rune(value)) > len([]rune(suffix))+1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package enrich
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+func TestMatchEnding(t *testing.T) {
+	tests := []struct {
+		value string
+		rules genderEndings
+		want  gender.Gender
+		ok    bool
+	}{
+		{"ивановна", patronymicEndings, gender.GenderFemale, true},
+		{"ильинична", patronymicEndings, gender.GenderFemale, true},
+		{"петрович", patronymicEndings, gender.GenderMale, true},
+		{"petrovich", patronymicEndings, gender.GenderMale, true},
+		{"ivanovna", patronymicEndings, gender.GenderFemale, true},
+		{"мамед оглы", patronymicEndings, gender.GenderMale, true},
+		{"алиева кызы", patronymicEndings, gender.GenderFemale, true},
+		{"оглы", patronymicEndings, "", false},
+		{"иванова", surnameEndings, gender.GenderFemale, true},
+		{"иванов", surnameEndings, gender.GenderMale, true},
+		{"ivanova", surnameEndings, gender.GenderFemale, true},
+		{"достоевский", surnameEndings, gender.GenderMale, true},
+		{"толстая", surnameEndings, "", false},
+		{"римский-корсаков", surnameEndings, gender.GenderMale, true},
+		{"римская-корсакова", surnameEndings, gender.GenderFemale, true},
+		{"ов", surnameEndings, "", false},
+		{"ким", surnameEndings, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := matchEnding(tt.value, tt.rules)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("matchEnding(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGenderRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  Query
+		want   gender.Gender
+		source string
+	}{
+		{"patronymic over surname", Query{Name: "Саша", Surname: "Иванов", Patronymic: "Петровна"}, gender.GenderFemale, SourcePatronymic},
+		{"cyrillic surname", Query{Name: "Саша", Surname: "Иванова"}, gender.GenderFemale, SourceSurname},
+		{"latin surname without country", Query{Name: "Sasha", Surname: "Ivanova"}, "", ""},
+		{"latin surname in russia", Query{Name: "Sasha", Surname: "Ivanova", CountryID: "ru"}, gender.GenderFemale, SourceSurname},
+		{"spanish surname", Query{Name: "John", Surname: "Medina"}, "", ""},
+		{"italian surname", Query{Name: "John", Surname: "Messina", CountryID: "IT"}, "", ""},
+		{"french surname", Query{Name: "Anna", Surname: "Martin", CountryID: "FR"}, "", ""},
+	}
+	rules := NewGenderRules(nil)
+	for _, tt := range tests {
+		res, err := rules.Enrich(context.Background(), tt.query)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.Gender != tt.want || res.Sources[FieldGender] != tt.source {
+			t.Errorf("%s: got %q from %q, want %q from %q",
+				tt.name, res.Gender, res.Sources[FieldGender], tt.want, tt.source)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package enrich
+
+import (
+	"context"
+	"maps"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+// Thresholds is the confidence a provider answer needs to be trusted. Zero
+// values disable the check.
+type Thresholds struct {
+	MinProbability float64
+	MinCount       int
+}
+
+// Threshold drops the values next predicted with too little confidence: a
+// probability or a sample count below the thresholds. Such a gender becomes
+// unknown and an age or nationality is left empty, while the raw answer stays
+// in Predictions.
+type Threshold struct {
+	next       Enricher
+	thresholds Thresholds
+}
+
+func NewThreshold(next Enricher, thresholds Thresholds) *Threshold {
+	return &Threshold{next: next, thresholds: thresholds}
+}
+
+func (t *Threshold) Name() string {
+	return t.next.Name()
+}
+
+func (t *Threshold) Enrich(ctx context.Context, q Query) (Result, error) {
+	res, err := t.next.Enrich(ctx, q)
+	if err != nil {
+		return res, err
+	}
+	// the result may be shared with other callers, e.g. by the Batcher
+	res.Sources = maps.Clone(res.Sources)
+	for _, p := range res.Predictions {
+		if p.Provider != t.Name() || t.confident(p) {
+			continue
+		}
+		for field, source := range res.Sources {
+			if source != t.Name() {
+				continue
+			}
+			switch field {
+			case FieldGender:
+				res.Gender = gender.Unknown
+			case FieldAge:
+				res.Age = nil
+			case FieldNationality:
+				res.Nationality = ""
+			}
+			delete(res.Sources, field)
+			lowConfidence.WithLabelValues(t.Name()).Inc()
+		}
+	}
+	return res, nil
+}
+
+func (t *Threshold) confident(p Prediction) bool {
+	if p.Probability != nil && *p.Probability < t.thresholds.MinProbability {
+		return false
+	}
+	return p.Count >= t.thresholds.MinCount
+}
@@ -0,0 +1,110 @@
+package enrich_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	"github.com/Sanchir01/users-info/internal/enrich/enrichtest"
+	"github.com/Sanchir01/users-info/internal/gender"
+)
+
+const thresholdFixtures = `[
+	{"name": "sure", "gender": "male", "probability": 0.98, "count": 5000, "age": 40, "country": [{"country_id": "RU", "probability": 0.8}]},
+	{"name": "unsure", "gender": "female", "probability": 0.55, "count": 5000, "age": 30, "country": [{"country_id": "KZ", "probability": 0.2}]},
+	{"name": "rare", "gender": "male", "probability": 1.0, "count": 3, "age": 25, "country": [{"country_id": "BY", "probability": 0.9}]}
+]`
+
+func thresholdServer(t *testing.T) string {
+	t.Helper()
+	fixtures, err := enrichtest.ParseFixtures([]byte(thresholdFixtures))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, _ := enrichtest.NewTestServer(fixtures, enrichtest.Faults{})
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestThreshold(t *testing.T) {
+	baseURL := thresholdServer(t)
+	providers := map[enrich.Field]enrich.Enricher{
+		enrich.FieldGender:      enrich.NewGenderize(http.DefaultClient, enrichtest.Endpoint(baseURL, "genderize")),
+		enrich.FieldAge:         enrich.NewAgify(http.DefaultClient, enrichtest.Endpoint(baseURL, "agify")),
+		enrich.FieldNationality: enrich.NewNationalize(http.DefaultClient, enrichtest.Endpoint(baseURL, "nationalize")),
+	}
+	strict := enrich.Thresholds{MinProbability: 0.6, MinCount: 10}
+	tests := []struct {
+		name       string
+		field      enrich.Field
+		thresholds enrich.Thresholds
+		trusted    bool
+	}{
+		{"sure", enrich.FieldGender, strict, true},
+		{"unsure", enrich.FieldGender, strict, false},
+		{"rare", enrich.FieldGender, strict, false},
+		{"unsure", enrich.FieldGender, enrich.Thresholds{}, true},
+		{"sure", enrich.FieldAge, strict, true},
+		{"unsure", enrich.FieldAge, strict, true},
+		{"rare", enrich.FieldAge, strict, false},
+		{"sure", enrich.FieldNationality, strict, true},
+		{"unsure", enrich.FieldNationality, strict, false},
+		{"rare", enrich.FieldNationality, strict, false},
+	}
+	for _, tt := range tests {
+		e := enrich.NewThreshold(providers[tt.field], tt.thresholds)
+		res, err := e.Enrich(context.Background(), enrich.Query{Name: tt.name})
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.field, tt.name, err)
+		}
+		kept := map[enrich.Field]bool{
+			enrich.FieldGender:      res.Gender != gender.Unknown,
+			enrich.FieldAge:         res.Age != nil,
+			enrich.FieldNationality: res.Nationality != "",
+		}[tt.field]
+		if kept != tt.trusted {
+			t.Errorf("%s %s: value kept = %v, want %v", tt.field, tt.name, kept, tt.trusted)
+		}
+		if _, ok := res.Sources[tt.field]; ok != tt.trusted {
+			t.Errorf("%s %s: sources %v", tt.field, tt.name, res.Sources)
+		}
+		if len(res.Predictions) != 1 {
+			t.Errorf("%s %s: the raw answer is not kept: %v", tt.field, tt.name, res.Predictions)
+		}
+	}
+}
+
+// Batched callers of the same name must not share the sources the threshold
+// drops; run with -race.
+func TestThresholdConcurrentBatchedLookups(t *testing.T) {
+	baseURL := thresholdServer(t)
+	batcher := enrich.NewBatcher(
+		enrich.NewGenderize(http.DefaultClient, enrichtest.Endpoint(baseURL, "genderize")),
+		20*time.Millisecond, enrich.MaxBatchSize,
+	)
+	e := enrich.NewThreshold(batcher, enrich.Thresholds{MinProbability: 0.6})
+
+	var wg sync.WaitGroup
+	results := make([]enrich.Result, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := e.Enrich(context.Background(), enrich.Query{Name: "unsure"})
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	for i, res := range results {
+		if res.Gender != gender.Unknown || len(res.Sources) != 0 {
+			t.Errorf("caller %d: gender %q from %v, want unknown without a source", i, res.Gender, res.Sources)
+		}
+	}
+}
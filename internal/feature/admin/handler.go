@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
+	"github.com/Sanchir01/users-info/pkg/lib/logger/sl"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.52.2 --name=AdminHandlers
+type AdminHandlers interface {
+	Budgets(ctx context.Context) ([]enrich.QuotaBudget, error)
+}
+type Handler struct {
+	service AdminHandlers
+	Log     *slog.Logger
+}
+
+func NewHandler(service AdminHandlers, lg *slog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		Log:     lg,
+	}
+}
+
+type GetQuotaResponse struct {
+	api.Response
+	Budgets []enrich.QuotaBudget `json:"budgets"`
+}
+
+// @Tags admin
+// @Description get the remaining request budget of every enrichment provider
+// @Accept json
+// @Produce json
+// @Success 200 {object}  GetQuotaResponse
+// @Failure 500 {object}  api.ProblemDetails
+// @Router /admin/enrichment/quota [get]
+func (h *Handler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	const op = "admin.Handler.GetQuota"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	budgets, err := h.service.Budgets(r.Context())
+	if err != nil {
+		log.Error("fail get enrichment quota", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
+	}
+	log.Info("get enrichment quota success")
+
+	render.JSON(w, r, GetQuotaResponse{
+		Response: api.OK(),
+		Budgets:  budgets,
+	})
+}
@@ -1,8 +1,11 @@
 package user
 
 import (
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Sanchir01/users-info/internal/enrich"
 	"github.com/Sanchir01/users-info/internal/gender"
 	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
 	"github.com/google/uuid"
@@ -12,10 +15,82 @@ type CreateUserRequest struct {
 	Name       string `json:"name" validate:"required,min=1,max=100"`
 	Surname    string `json:"surname" validate:"required,min=1,max=100"`
 	Patronymic string `json:"patronymic,omitempty" validate:"omitempty,max=100"`
+	// CountryHint localizes age and gender predictions, e.g. "RU"
+	CountryHint string `json:"country_hint,omitempty" validate:"omitempty,iso3166_1_alpha2"`
 }
+
+func (r CreateUserRequest) query() enrich.Query {
+	return enrich.Query{Name: r.Name, Surname: r.Surname, Patronymic: r.Patronymic, CountryID: r.CountryHint}
+}
+
+// PreviewEnrichmentRequest describes a person that is not stored yet.
+type PreviewEnrichmentRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Surname     string `json:"surname" validate:"required,min=1,max=100"`
+	Patronymic  string `json:"patronymic,omitempty" validate:"omitempty,max=100"`
+	CountryHint string `json:"country_hint,omitempty" validate:"omitempty,iso3166_1_alpha2"`
+}
+
+func (r PreviewEnrichmentRequest) query() enrich.Query {
+	return enrich.Query{Name: r.Name, Surname: r.Surname, Patronymic: r.Patronymic, CountryID: r.CountryHint}
+}
+
+// PreviewEnrichmentResponse is the enrichment a user with the requested
+// name would get, with the raw provider answers and their confidences.
+type PreviewEnrichmentResponse struct {
+	api.Response
+	Age              *int                    `json:"age"`
+	Gender           gender.Gender           `json:"gender"`
+	Nationality      *string                 `json:"nationality"`
+	EnrichmentStatus EnrichmentStatus        `json:"enrichment_status"`
+	UnenrichedFields []enrich.Field          `json:"unenriched_fields,omitempty"`
+	EnrichmentMode   enrich.LocalizationMode `json:"enrichment_mode,omitempty"`
+	Sources          map[enrich.Field]string `json:"sources,omitempty"`
+	// CachedFields lists the fields whose value was served from the cache.
+	CachedFields []enrich.Field      `json:"cached_fields,omitempty"`
+	Predictions  []enrich.Prediction `json:"predictions"`
+}
+
+func newPreviewEnrichmentResponse(res enrich.Result) PreviewEnrichmentResponse {
+	missing := res.Missing()
+	preview := PreviewEnrichmentResponse{
+		Response:         api.OK(),
+		Age:              res.Age,
+		Gender:           res.Gender,
+		EnrichmentStatus: enrichmentStatus(missing),
+		UnenrichedFields: missing,
+		EnrichmentMode:   res.Mode,
+		Sources:          res.Sources,
+		Predictions:      res.Predictions,
+	}
+	if preview.Gender == "" {
+		preview.Gender = gender.Unknown
+	}
+	if res.Nationality != "" {
+		preview.Nationality = &res.Nationality
+	}
+	if preview.Predictions == nil {
+		preview.Predictions = []enrich.Prediction{}
+	}
+	for _, field := range []enrich.Field{enrich.FieldGender, enrich.FieldAge, enrich.FieldNationality} {
+		source, ok := res.Sources[field]
+		if !ok {
+			continue
+		}
+		for _, p := range res.Predictions {
+			if p.Provider == source && p.Cached {
+				preview.CachedFields = append(preview.CachedFields, field)
+				break
+			}
+		}
+	}
+	return preview
+}
+
 type CreateUserResponse struct {
 	api.Response
-	Ok string `json:"ok" validate:"required"`
+	ID uuid.UUID `json:"id"`
+	Ok string    `json:"ok" validate:"required"`
 }
 type GetAllUsersResponse struct {
 	api.Response
@@ -28,48 +103,183 @@ type PaginationParams struct {
 	Page     uint `json:"page" validate:"omitempty,gte=1"`
 	PageSize uint `json:"page_size" validate:"omitempty,gte=1,lte=100"`
 }
+
+// UpdateUserRequest may override enriched values. An overridden field is locked
+// and is not re-enriched until it is listed in Unlock.
 type UpdateUserRequest struct {
-	Name       string `json:"name" validate:"required,min=1,max=100"`
-	Surname    string `json:"surname" validate:"required,min=1,max=100"`
-	Patronymic string `json:"patronymic,omitempty" validate:"omitempty,max=100"`
+	Name        string         `json:"name" validate:"required,min=1,max=100"`
+	Surname     string         `json:"surname" validate:"required,min=1,max=100"`
+	Patronymic  string         `json:"patronymic,omitempty" validate:"omitempty,max=100"`
+	Age         *int           `json:"age,omitempty" validate:"omitempty,gte=0,lte=120"`
+	Gender      *gender.Gender `json:"gender,omitempty" validate:"omitempty,oneof=male female unknown"`
+	Nationality *string        `json:"nationality,omitempty" validate:"omitempty,iso3166_1_alpha2"`
+	Unlock      []enrich.Field `json:"unlock,omitempty" validate:"omitempty,dive,oneof=age gender nationality"`
+	// CountryHint replaces the stored hint when present.
+	CountryHint *string `json:"country_hint,omitempty" validate:"omitempty,iso3166_1_alpha2"`
+	// Reenrich asks the providers again even if the name did not change.
+	Reenrich bool `json:"reenrich,omitempty"`
+}
+
+// countryHint is the hint the user has after the update.
+func (r UpdateUserRequest) countryHint(state EnrichmentStateDB) string {
+	if r.CountryHint != nil {
+		return *r.CountryHint
+	}
+	return state.CountryHint
+}
+
+func (r UpdateUserRequest) query(state EnrichmentStateDB) enrich.Query {
+	return enrich.Query{Name: r.Name, Surname: r.Surname, Patronymic: r.Patronymic, CountryID: r.countryHint(state)}
+}
+
+// needsEnrichment reports whether the stored enrichment no longer applies:
+// the first name or the country hint changed, the surname or patronymic a
+// gender was inferred from changed, the last run did not succeed, or the
+// caller asked for it or unlocked fields.
+func (r UpdateUserRequest) needsEnrichment(state EnrichmentStateDB) bool {
+	if r.Reenrich || len(r.Unlock) > 0 {
+		return true
+	}
+	if state.EnrichmentStatus != EnrichmentDone && state.EnrichmentStatus != EnrichmentPartial {
+		return true
+	}
+	if enrich.NormalizeName(r.Name) != enrich.NormalizeName(state.Name) ||
+		!strings.EqualFold(r.countryHint(state), state.CountryHint) {
+		return true
+	}
+	switch state.EnrichmentSources[string(enrich.FieldGender)] {
+	case enrich.SourceSurname:
+		return enrich.NormalizeName(r.Surname) != enrich.NormalizeName(state.Surname)
+	case enrich.SourcePatronymic:
+		return enrich.NormalizeName(r.Patronymic) != enrich.NormalizeName(state.Patronymic)
+	}
+	return false
 }
+
+// overridden lists the fields the request sets manually.
+func (r UpdateUserRequest) overridden() []enrich.Field {
+	var fields []enrich.Field
+	if r.Age != nil {
+		fields = append(fields, enrich.FieldAge)
+	}
+	if r.Gender != nil {
+		fields = append(fields, enrich.FieldGender)
+	}
+	if r.Nationality != nil {
+		fields = append(fields, enrich.FieldNationality)
+	}
+	return fields
+}
+
 type UpdateUserResponse struct {
 	api.Response
-	Ok string `json:"ok" validate:"required"`
+	Ok               string                  `json:"ok" validate:"required"`
+	EnrichmentStatus EnrichmentStatus        `json:"enrichment_status"`
+	UnenrichedFields []enrich.Field          `json:"unenriched_fields,omitempty"`
+	EnrichmentMode   enrich.LocalizationMode `json:"enrichment_mode,omitempty"`
+}
+
+// UpdateUserResult is the enrichment state of an updated user. A renamed user
+// is pending until the workers enrich the new name.
+type UpdateUserResult struct {
+	Status     EnrichmentStatus
+	Unenriched []enrich.Field
+	Mode       enrich.LocalizationMode
 }
+
 type DeleteUserResponse struct {
 	api.Response
 	Ok string `json:"ok" validate:"required"`
 }
+type EnrichmentStatus string
+
+const (
+	EnrichmentPending EnrichmentStatus = "pending"
+	EnrichmentDone    EnrichmentStatus = "done"
+	EnrichmentPartial EnrichmentStatus = "partial"
+	EnrichmentFailed  EnrichmentStatus = "failed"
+)
+
+// enrichmentStatus reports partial when some of the fields could not be enriched.
+func enrichmentStatus(missing []enrich.Field) EnrichmentStatus {
+	if len(missing) > 0 {
+		return EnrichmentPartial
+	}
+	return EnrichmentDone
+}
+
+// unenrichedFields lists the fields the providers had no data for. Locked
+// fields are never enriched and are not reported.
+func unenrichedFields(res enrich.Result, locked []enrich.Field) []enrich.Field {
+	var missing []enrich.Field
+	for _, field := range res.Missing() {
+		if !slices.Contains(locked, field) {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+type GetUserResponse struct {
+	api.Response
+	User *UserDB `json:"user"`
+}
+type GetUserEnrichmentResponse struct {
+	api.Response
+	UserID     uuid.UUID       `json:"user_id"`
+	Enrichment []*EnrichmentDB `json:"enrichment"`
+}
+type GetUserEnrichmentHistoryResponse struct {
+	api.Response
+	UserID       uuid.UUID              `json:"user_id"`
+	History      []*EnrichmentHistoryDB `json:"history"`
+	Page         uint                   `json:"page"`
+	ItemsPerPage uint                   `json:"items_per_page"`
+}
+type EnrichmentDB struct {
+	Provider    string                     `db:"provider" json:"provider"`
+	InputName   string                     `db:"input_name" json:"input_name"`
+	CountryID   string                     `db:"country_id" json:"country_id,omitempty"`
+	Value       string                     `db:"value" json:"value"`
+	Probability *float64                   `db:"probability" json:"probability"`
+	Count       int                        `db:"sample_count" json:"count"`
+	Countries   []enrich.CountryPrediction `db:"countries" json:"countries,omitempty"`
+	FetchedAt   time.Time                  `db:"fetched_at" json:"fetched_at"`
+}
+
+// EnrichmentHistoryDB is one provider answer as it was received, kept even
+// after later enrichments replace it.
+type EnrichmentHistoryDB struct {
+	ID          int64                      `db:"id" json:"id"`
+	Provider    string                     `db:"provider" json:"provider"`
+	InputName   string                     `db:"input_name" json:"input_name"`
+	CountryID   string                     `db:"country_id" json:"country_id,omitempty"`
+	Value       string                     `db:"value" json:"value"`
+	Probability *float64                   `db:"probability" json:"probability,omitempty"`
+	Count       int                        `db:"sample_count" json:"count"`
+	Countries   []enrich.CountryPrediction `db:"countries" json:"countries,omitempty"`
+	FetchedAt   time.Time                  `db:"fetched_at" json:"fetched_at"`
+	RecordedAt  time.Time                  `db:"recorded_at" json:"recorded_at"`
+}
 type UserDB struct {
-	ID          uuid.UUID     `db:"id" json:"id"`
-	Name        string        `db:"name" json:"name"`
-	Surname     string        `db:"surname" json:"surname"`
-	Patronymic  string        `db:"patronymic" json:"patronymic,omitempty"`
-	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time     `db:"updated_at" json:"updated_at"`
-	Age         int           `db:"age" json:"age"`
-	Gender      gender.Gender `db:"gender" json:"gender"`
-	Nationality string        `db:"nationality" json:"nationality"`
-	Version     int64         `db:"version" json:"version"`
-}
-type NationalizeResponse struct {
-	Name    string              `json:"name"`
-	Country []CountryPrediction `json:"country"`
-}
-
-type CountryPrediction struct {
-	CountryID   string  `json:"country_id"`
-	Probability float64 `json:"probability"`
-}
-type GenderizeResponse struct {
-	Name        string        `json:"name" validate:"required"`
-	Gender      gender.Gender `json:"gender" validate:"required"`
-	Probability float64       `json:"probability" validate:"required,gte=0,lte=1"`
-	Count       int           `json:"count" validate:"required,gte=0"`
-}
-type UserAgeResponse struct {
-	Name  string `json:"name" validate:"required,min=1,max=100"`
-	Age   int    `json:"age" validate:"required,gte=0,lte=120"`
-	Count int    `json:"count" validate:"gte=0"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	Name              string           `db:"name" json:"name"`
+	Surname           string           `db:"surname" json:"surname"`
+	Patronymic        string           `db:"patronymic" json:"patronymic,omitempty"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	Age               *int             `db:"age" json:"age"`
+	Gender            *gender.Gender   `db:"gender" json:"gender"`
+	Nationality       *string          `db:"nationality" json:"nationality"`
+	EnrichmentStatus  EnrichmentStatus `db:"enrichment_status" json:"enrichment_status"`
+	EnrichedAt        *time.Time       `db:"enriched_at" json:"enriched_at,omitempty"`
+	UnenrichedFields  []string         `db:"unenriched_fields" json:"unenriched_fields,omitempty"`
+	AgeManual         bool             `db:"age_manual" json:"age_manual"`
+	GenderManual      bool             `db:"gender_manual" json:"gender_manual"`
+	NationalityManual bool             `db:"nationality_manual" json:"nationality_manual"`
+	CountryHint       string           `db:"country_hint" json:"country_hint,omitempty"`
+	EnrichmentMode    string           `db:"enrichment_mode" json:"enrichment_mode,omitempty"`
+	// EnrichmentSources names the provider, rule or "manual" each field came from.
+	EnrichmentSources map[string]string `db:"enrichment_sources" json:"enrichment_sources,omitempty"`
+	Version           int64             `db:"version" json:"version"`
 }
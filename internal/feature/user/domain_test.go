@@ -0,0 +1,86 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+)
+
+func TestNeedsEnrichment(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	stored := EnrichmentStateDB{
+		Name:             "Иван",
+		Surname:          "Петров",
+		Patronymic:       "Сергеевич",
+		CountryHint:      "RU",
+		EnrichmentStatus: EnrichmentDone,
+		EnrichmentSources: map[string]string{
+			string(enrich.FieldGender): "genderize",
+			string(enrich.FieldAge):    "agify",
+		},
+	}
+	withStatus := func(status EnrichmentStatus) EnrichmentStateDB {
+		s := stored
+		s.EnrichmentStatus = status
+		return s
+	}
+	withGenderSource := func(source string) EnrichmentStateDB {
+		s := stored
+		s.EnrichmentSources = map[string]string{string(enrich.FieldGender): source}
+		return s
+	}
+	same := UpdateUserRequest{Name: "Иван", Surname: "Петров", Patronymic: "Сергеевич"}
+	with := func(change func(*UpdateUserRequest)) UpdateUserRequest {
+		r := same
+		change(&r)
+		return r
+	}
+	tests := []struct {
+		name  string
+		req   UpdateUserRequest
+		state EnrichmentStateDB
+		want  bool
+	}{
+		{"nothing changed", same, stored, false},
+		{"partial result is kept", same, withStatus(EnrichmentPartial), false},
+		{"name spelled differently", with(func(r *UpdateUserRequest) { r.Name = "  иВАН " }), stored, false},
+		{"name changed", with(func(r *UpdateUserRequest) { r.Name = "Пётр" }), stored, true},
+		{"same country hint", with(func(r *UpdateUserRequest) { r.CountryHint = ptr("ru") }), stored, false},
+		{"country hint changed", with(func(r *UpdateUserRequest) { r.CountryHint = ptr("KZ") }), stored, true},
+		{"country hint added", with(func(r *UpdateUserRequest) { r.CountryHint = ptr("BY") }), EnrichmentStateDB{
+			Name: "Иван", Surname: "Петров", Patronymic: "Сергеевич", EnrichmentStatus: EnrichmentDone,
+		}, true},
+		{"failed run", same, withStatus(EnrichmentFailed), true},
+		{"pending run", same, withStatus(EnrichmentPending), true},
+		{"asked for", with(func(r *UpdateUserRequest) { r.Reenrich = true }), stored, true},
+		{"field unlocked", with(func(r *UpdateUserRequest) { r.Unlock = []enrich.Field{enrich.FieldAge} }), stored, true},
+		{"surname changed, gender from provider", with(func(r *UpdateUserRequest) { r.Surname = "Петрова" }), stored, false},
+		{"surname changed, gender from surname", with(func(r *UpdateUserRequest) { r.Surname = "Петрова" }), withGenderSource(enrich.SourceSurname), true},
+		{"patronymic kept, gender from surname", with(func(r *UpdateUserRequest) { r.Patronymic = "" }), withGenderSource(enrich.SourceSurname), false},
+		{"patronymic changed, gender from patronymic", with(func(r *UpdateUserRequest) { r.Patronymic = "Сергеевна" }), withGenderSource(enrich.SourcePatronymic), true},
+		{"surname changed, gender from patronymic", with(func(r *UpdateUserRequest) { r.Surname = "Петрова" }), withGenderSource(enrich.SourcePatronymic), false},
+	}
+	for _, tt := range tests {
+		if got := tt.req.needsEnrichment(tt.state); got != tt.want {
+			t.Errorf("%s: needsEnrichment = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateQueryKeepsCountryHint(t *testing.T) {
+	hint := "KZ"
+	state := EnrichmentStateDB{Name: "Иван", CountryHint: "RU"}
+	tests := []struct {
+		hint *string
+		want string
+	}{
+		{nil, "RU"},
+		{&hint, "KZ"},
+	}
+	for _, tt := range tests {
+		r := UpdateUserRequest{Name: "Пётр", CountryHint: tt.hint}
+		if got := r.query(state); got.Name != "Пётр" || got.CountryID != tt.want {
+			t.Errorf("query = %+v, want Пётр in %s", got, tt.want)
+		}
+	}
+}
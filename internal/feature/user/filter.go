@@ -0,0 +1,189 @@
+package user
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultPage     = 1
+	defaultPageSize = 10
+)
+
+// UserFilter narrows the user list, empty fields do not filter. Names match
+// case-insensitively, exactly or by prefix, time ranges are inclusive.
+type UserFilter struct {
+	MinAge           *int               `json:"min_age" validate:"omitempty,gte=0"`
+	MaxAge           *int               `json:"max_age" validate:"omitempty,gte=0"`
+	Gender           []gender.Gender    `json:"gender" validate:"omitempty,dive,oneof=male female unknown"`
+	Nationality      []string           `json:"nationality" validate:"omitempty,dive,iso3166_1_alpha2"`
+	Name             string             `json:"name" validate:"omitempty,max=100"`
+	NamePrefix       string             `json:"name_prefix" validate:"omitempty,max=100"`
+	Surname          string             `json:"surname" validate:"omitempty,max=100"`
+	SurnamePrefix    string             `json:"surname_prefix" validate:"omitempty,max=100"`
+	Patronymic       string             `json:"patronymic" validate:"omitempty,max=100"`
+	PatronymicPrefix string             `json:"patronymic_prefix" validate:"omitempty,max=100"`
+	CreatedFrom      *time.Time         `json:"created_from"`
+	CreatedTo        *time.Time         `json:"created_to"`
+	UpdatedFrom      *time.Time         `json:"updated_from"`
+	UpdatedTo        *time.Time         `json:"updated_to"`
+	EnrichmentStatus []EnrichmentStatus `json:"enrichment_status" validate:"omitempty,dive,oneof=pending done partial failed"`
+}
+
+func init() {
+	api.Validate.RegisterStructValidation(validateUserFilter, UserFilter{})
+}
+
+// validateUserFilter rejects ranges whose upper bound is below the lower one,
+// a bound given alone is fine.
+func validateUserFilter(sl validator.StructLevel) {
+	f := sl.Current().Interface().(UserFilter)
+	if f.MinAge != nil && f.MaxAge != nil && *f.MaxAge < *f.MinAge {
+		sl.ReportError(f.MaxAge, "max_age", "MaxAge", "gtefield", "min_age")
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && f.CreatedTo.Before(*f.CreatedFrom) {
+		sl.ReportError(f.CreatedTo, "created_to", "CreatedTo", "gtefield", "created_from")
+	}
+	if f.UpdatedFrom != nil && f.UpdatedTo != nil && f.UpdatedTo.Before(*f.UpdatedFrom) {
+		sl.ReportError(f.UpdatedTo, "updated_to", "UpdatedTo", "gtefield", "updated_from")
+	}
+}
+
+// ListUsersQuery holds the query parameters of the user list.
+type ListUsersQuery struct {
+	PaginationParams
+	UserFilter
+}
+
+// parseListUsersQuery reads the parameters of the user list. Lists may be
+// repeated or comma separated, times are RFC 3339 or dates; a date as the
+// upper bound of a range covers the whole day.
+func parseListUsersQuery(values url.Values) (ListUsersQuery, error) {
+	p := queryParser{values: values}
+	q := ListUsersQuery{
+		PaginationParams: p.pagination(),
+		UserFilter: UserFilter{
+			MinAge:           p.int("min_age"),
+			MaxAge:           p.int("max_age"),
+			Gender:           listOf[gender.Gender](p.list("gender", strings.ToLower)),
+			Nationality:      p.list("nationality", strings.ToUpper),
+			Name:             p.string("name"),
+			NamePrefix:       p.string("name_prefix"),
+			Surname:          p.string("surname"),
+			SurnamePrefix:    p.string("surname_prefix"),
+			Patronymic:       p.string("patronymic"),
+			PatronymicPrefix: p.string("patronymic_prefix"),
+			CreatedFrom:      p.time("created_from", false),
+			CreatedTo:        p.time("created_to", true),
+			UpdatedFrom:      p.time("updated_from", false),
+			UpdatedTo:        p.time("updated_to", true),
+			EnrichmentStatus: listOf[EnrichmentStatus](p.list("enrichment_status", strings.ToLower)),
+		},
+	}
+	return q, p.err
+}
+
+// parsePaginationQuery reads the page and page_size parameters of a list.
+func parsePaginationQuery(values url.Values) (PaginationParams, error) {
+	p := queryParser{values: values}
+	pagination := p.pagination()
+	return pagination, p.err
+}
+
+// queryParser keeps the first unreadable parameter, later reads are skipped.
+type queryParser struct {
+	values url.Values
+	err    error
+}
+
+func (p *queryParser) string(name string) string {
+	return strings.TrimSpace(p.values.Get(name))
+}
+
+func (p *queryParser) fail(name string) {
+	if p.err == nil {
+		p.err = &api.ParamError{Param: name}
+	}
+}
+
+func (p *queryParser) int(name string) *int {
+	value := p.string(name)
+	if value == "" || p.err != nil {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	return &n
+}
+
+func (p *queryParser) pagination() PaginationParams {
+	return PaginationParams{
+		Page:     p.uint("page", defaultPage),
+		PageSize: p.uint("page_size", defaultPageSize),
+	}
+}
+
+// uint reads a positive number, def when the parameter is absent.
+func (p *queryParser) uint(name string, def uint) uint {
+	value := p.string(name)
+	if value == "" || p.err != nil {
+		return def
+	}
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || n == 0 {
+		p.fail(name)
+		return def
+	}
+	return uint(n)
+}
+
+func (p *queryParser) time(name string, upper bool) *time.Time {
+	value := p.string(name)
+	if value == "" || p.err != nil {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		p.fail(name)
+		return nil
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t
+}
+
+func (p *queryParser) list(name string, normalize func(string) string) []string {
+	var list []string
+	for _, value := range p.values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, normalize(item))
+			}
+		}
+	}
+	return list
+}
+
+func listOf[T, S ~string](values []S) []T {
+	if values == nil {
+		return nil
+	}
+	list := make([]T, len(values))
+	for i, value := range values {
+		list[i] = T(value)
+	}
+	return list
+}
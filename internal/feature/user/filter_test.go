@@ -0,0 +1,133 @@
+package user
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/gender"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
+	"github.com/go-playground/validator/v10"
+)
+
+func TestParseListUsersQuery(t *testing.T) {
+	age := func(n int) *int { return &n }
+	at := func(value string) *time.Time {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			panic(err)
+		}
+		return &t
+	}
+	defaults := PaginationParams{Page: defaultPage, PageSize: defaultPageSize}
+	tests := []struct {
+		name  string
+		query string
+		want  ListUsersQuery
+		param string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  ListUsersQuery{PaginationParams: defaults},
+		},
+		{
+			name:  "pagination",
+			query: "page=3&page_size=25",
+			want:  ListUsersQuery{PaginationParams: PaginationParams{Page: 3, PageSize: 25}},
+		},
+		{
+			name:  "lists split by commas and repeated",
+			query: "gender=Male,%20female&gender=&nationality=ru,ua&nationality=by",
+			want: ListUsersQuery{PaginationParams: defaults, UserFilter: UserFilter{
+				Gender:      []gender.Gender{gender.GenderMale, gender.GenderFemale},
+				Nationality: []string{"RU", "UA", "BY"},
+			}},
+		},
+		{
+			name:  "ages and names",
+			query: "min_age=18&max_age=%2030&name_prefix=%20Ив%20&surname=Petrov",
+			want: ListUsersQuery{PaginationParams: defaults, UserFilter: UserFilter{
+				MinAge:     age(18),
+				MaxAge:     age(30),
+				NamePrefix: "Ив",
+				Surname:    "Petrov",
+			}},
+		},
+		{
+			name:  "date bounds cover whole days",
+			query: "created_from=2025-06-01&created_to=2025-06-02&updated_to=2025-06-03T10:00:00Z",
+			want: ListUsersQuery{PaginationParams: defaults, UserFilter: UserFilter{
+				CreatedFrom: at("2025-06-01T00:00:00Z"),
+				CreatedTo:   at("2025-06-02T23:59:59.999999999Z"),
+				UpdatedTo:   at("2025-06-03T10:00:00Z"),
+			}},
+		},
+		{name: "bad int", query: "min_age=old", param: "min_age"},
+		{name: "bad time", query: "updated_from=yesterday", param: "updated_from"},
+		{name: "zero page", query: "page=0", param: "page"},
+		{name: "zero page size", query: "page_size=0", param: "page_size"},
+		{name: "negative page", query: "page=-1", param: "page"},
+		{name: "first bad parameter wins", query: "page=x&min_age=y", param: "page"},
+	}
+	for _, tt := range tests {
+		values, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parseListUsersQuery(values)
+		if tt.param != "" {
+			var paramErr *api.ParamError
+			if !errors.As(err, &paramErr) || paramErr.Param != tt.param {
+				t.Errorf("%s: got error %v, want invalid %s", tt.name, err, tt.param)
+			}
+			if !errors.Is(err, api.ErrBadRequest) {
+				t.Errorf("%s: %v is not a bad request", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateListUsersQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		field string
+	}{
+		{"min_age=18&max_age=30", ""},
+		{"max_age=30", ""},
+		{"min_age=30&max_age=18", "max_age"},
+		{"created_from=2025-06-02&created_to=2025-06-01", "created_to"},
+		{"page_size=101", "page_size"},
+		{"gender=robot", "gender[0]"},
+		{"nationality=XX", "nationality[0]"},
+		{"enrichment_status=done,lost", "enrichment_status[1]"},
+	}
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.query)
+		q, err := parseListUsersQuery(values)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		err = api.Validate.Struct(q)
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.query, err)
+			}
+			continue
+		}
+		var errs validator.ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field() != tt.field {
+			t.Errorf("%s: got %v, want an error on %s", tt.query, err, tt.field)
+		}
+	}
+}
@@ -7,24 +7,28 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/Sanchir01/users-info/internal/enrich"
 	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
 	"github.com/Sanchir01/users-info/pkg/lib/logger/sl"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.52.2 --name=UserHandlers
 type UserHandlers interface {
-	GetAllUsers(ctx context.Context, page, pageSize uint, minAge, maxAge *int) ([]*UserDB, error)
+	GetAllUsers(ctx context.Context, page, pageSize uint, filter UserFilter) ([]*UserDB, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*UserDB, error)
+	GetUserEnrichment(ctx context.Context, id uuid.UUID) ([]*EnrichmentDB, error)
+	GetUserEnrichmentHistory(ctx context.Context, id uuid.UUID, page, pageSize uint) ([]*EnrichmentHistoryDB, error)
 	DeleteUserByID(ctx context.Context, id uuid.UUID) error
-	UpdateUser(ctx context.Context, id uuid.UUID, name, surname, patronymic string) error
+	PreviewEnrichment(ctx context.Context, req PreviewEnrichmentRequest) (enrich.Result, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, req UpdateUserRequest) (UpdateUserResult, error)
 	CreateUserService(
-		name, surname, patronymic string,
+		req CreateUserRequest,
 		ctx context.Context,
-	) error
+	) (uuid.UUID, error)
 }
 type Handler struct {
 	service UserHandlers
@@ -44,8 +48,8 @@ func NewHandler(service UserHandlers, lg *slog.Logger) *Handler {
 // @Produce json
 // @Param input body CreateUserRequest true "create body"
 // @Success 200 {object}  CreateUserResponse
-// @Failure 400,404 {object}  api.Response
-// @Failure 500 {object}  api.Response
+// @Failure 400,422 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
 // @Router /users/create [post]
 func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	const op = "user.Handler.CreateUser"
@@ -56,40 +60,93 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("failed to decode request body", slog.Any("err", err))
-		render.JSON(w, r, api.Error("Ошибка при валидации тела"))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrBadRequest, err))
 		return
 	}
 	log.Info("request body decoded", slog.Any("request", req))
-	if err := validator.New().Struct(req); err != nil {
+	if err := api.Validate.Struct(req); err != nil {
 		log.Error("invalid request", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrValidation, err))
 		return
 	}
-	if err := h.service.CreateUserService(req.Name, req.Surname, req.Patronymic, r.Context()); err != nil {
+	id, err := h.service.CreateUserService(req, r.Context())
+	if err != nil {
 		log.Error("fail create user", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
-
+		api.RenderProblem(w, r, err)
 		return
 	}
-	log.Info("success create user")
+	log.Info("success create user", slog.String("user_id", id.String()))
 
 	render.JSON(w, r, CreateUserResponse{
 		Response: api.OK(),
-		Ok:       "user created successfully",
+		ID:       id,
+		Ok:       api.T(r, api.MsgUserCreated),
 	})
 }
 
 // @Tags user
-// @Description get all users
+// @Description preview the enrichment of a person without creating a user
+// @Accept json
+// @Produce json
+// @Param input body PreviewEnrichmentRequest true "preview body"
+// @Success 200 {object}  PreviewEnrichmentResponse
+// @Failure 400,422 {object}  api.ProblemDetails
+// @Failure 502,503 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
+// @Router /enrich/preview [post]
+func (h *Handler) PreviewEnrichment(w http.ResponseWriter, r *http.Request) {
+	const op = "user.Handler.PreviewEnrichment"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+	var req PreviewEnrichmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", slog.Any("err", err))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrBadRequest, err))
+		return
+	}
+	log.Info("request body decoded", slog.Any("request", req))
+	if err := api.Validate.Struct(req); err != nil {
+		log.Error("invalid request", sl.Err(err))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrValidation, err))
+		return
+	}
+	enriched, err := h.service.PreviewEnrichment(r.Context(), req)
+	if err != nil {
+		log.Error("fail preview enrichment", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
+	}
+	log.Info("preview enrichment success")
+
+	render.JSON(w, r, newPreviewEnrichmentResponse(enriched))
+}
+
+// @Tags user
+// @Description get all users, list filters may be repeated or comma separated
 // @Accept json
 // @Produce json
 // @Param page query int false "page number" default(1) minimum(1)
 // @Param page_size query int false "items per page" default(10) minimum(1) maximum(100)
-// @Param min_age query int false "minimum age filter"
-// @Param max_age query int false "maximum age filter"
+// @Param min_age query int false "minimum age filter" minimum(0)
+// @Param max_age query int false "maximum age filter" minimum(0)
+// @Param gender query []string false "gender filter" Enums(male, female, unknown) collectionFormat(multi)
+// @Param nationality query []string false "ISO 3166-1 alpha-2 nationality filter" collectionFormat(multi)
+// @Param name query string false "exact name, case-insensitive"
+// @Param name_prefix query string false "name prefix, case-insensitive"
+// @Param surname query string false "exact surname, case-insensitive"
+// @Param surname_prefix query string false "surname prefix, case-insensitive"
+// @Param patronymic query string false "exact patronymic, case-insensitive"
+// @Param patronymic_prefix query string false "patronymic prefix, case-insensitive"
+// @Param created_from query string false "created at or after, RFC 3339 or date"
+// @Param created_to query string false "created at or before, RFC 3339 or date"
+// @Param updated_from query string false "updated at or after, RFC 3339 or date"
+// @Param updated_to query string false "updated at or before, RFC 3339 or date"
+// @Param enrichment_status query []string false "enrichment status filter" Enums(pending, done, partial, failed) collectionFormat(multi)
 // @Success 200 {object}  GetAllUsersResponse
-// @Failure 400,404 {object}  api.Response
-// @Failure 500 {object}  api.Response
+// @Failure 400,422 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
 // @Router /users [get]
 func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	const op = "user.Handler.GetAllUsers"
@@ -98,81 +155,169 @@ func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		slog.String("request_id", middleware.GetReqID(r.Context())),
 	)
 
-	pageStr := r.URL.Query().Get("page")
-	pageSizeStr := r.URL.Query().Get("page_size")
-	minAgeStr := r.URL.Query().Get("min_age")
-	maxAgeStr := r.URL.Query().Get("max_age")
-
-	var page uint = 1
-	var pageSize uint = 10
-	var minAge, maxAge *int
-
-	if pageStr != "" {
-		var pageInt int
-		_, err := fmt.Sscanf(pageStr, "%d", &pageInt)
-		if err == nil && pageInt > 0 {
-			page = uint(pageInt)
-		} else {
-			log.Warn("invalid page parameter", slog.String("page", pageStr))
-		}
+	query, err := parseListUsersQuery(r.URL.Query())
+	if err != nil {
+		log.Error("failed to parse query parameters", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
+	}
+	if err := api.Validate.Struct(query); err != nil {
+		log.Error("invalid query parameters", sl.Err(err))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrValidation, err))
+		return
 	}
 
-	if pageSizeStr != "" {
-		var pageSizeInt int
-		_, err := fmt.Sscanf(pageSizeStr, "%d", &pageSizeInt)
-		if err == nil && pageSizeInt > 0 && pageSizeInt <= 100 {
-			pageSize = uint(pageSizeInt)
-		} else {
-			log.Warn("invalid page_size parameter", slog.String("page_size", pageSizeStr))
-		}
+	users, err := h.service.GetAllUsers(r.Context(), query.Page, query.PageSize, query.UserFilter)
+	if err != nil {
+		log.Error("fail get all users", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
 	}
+	log.Info("get all users success",
+		slog.Uint64("page", uint64(query.Page)),
+		slog.Uint64("page_size", uint64(query.PageSize)),
+		slog.Any("filter", query.UserFilter),
+	)
+
+	render.JSON(w, r, GetAllUsersResponse{
+		Response:     api.OK(),
+		Users:        users,
+		Page:         query.Page,
+		ItemsPerPage: query.PageSize,
+	})
+}
 
-	if minAgeStr != "" {
-		var minAgeInt int
-		_, err := fmt.Sscanf(minAgeStr, "%d", &minAgeInt)
-		if err == nil && minAgeInt >= 0 {
-			minAge = &minAgeInt
-			log.Info("filtering by min age", slog.Int("min_age", minAgeInt))
-		} else {
-			log.Warn("invalid min_age parameter", slog.String("min_age", minAgeStr))
-		}
+// @Tags user
+// @Description get user by id
+// @Param id path string true "user id"
+// @Accept json
+// @Produce json
+// @Success 200 {object}  GetUserResponse
+// @Failure 400,404 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
+// @Router /users/{id} [get]
+func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	const op = "user.Handler.GetUserByID"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	id := chi.URLParam(r, "id")
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		log.Error("invalid UUID format", sl.Err(err))
+		api.RenderProblem(w, r, api.ErrInvalidUUID)
+		return
 	}
 
-	if maxAgeStr != "" {
-		var maxAgeInt int
-		_, err := fmt.Sscanf(maxAgeStr, "%d", &maxAgeInt)
-		if err == nil && maxAgeInt >= 0 {
-			maxAge = &maxAgeInt
-			log.Info("filtering by max age", slog.Int("max_age", maxAgeInt))
-		} else {
-			log.Warn("invalid max_age parameter", slog.String("max_age", maxAgeStr))
-		}
+	user, err := h.service.GetUserByID(r.Context(), uuidID)
+	if err != nil {
+		log.Error("fail get user", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
 	}
+	log.Info("get user success")
+
+	render.JSON(w, r, GetUserResponse{
+		Response: api.OK(),
+		User:     user,
+	})
+}
+
+// @Tags user
+// @Description get enrichment details (confidence, sample size, all country predictions) of a user
+// @Param id path string true "user id"
+// @Accept json
+// @Produce json
+// @Success 200 {object}  GetUserEnrichmentResponse
+// @Failure 400,404 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
+// @Router /users/{id}/enrichment [get]
+func (h *Handler) GetUserEnrichment(w http.ResponseWriter, r *http.Request) {
+	const op = "user.Handler.GetUserEnrichment"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
 
-	users, err := h.service.GetAllUsers(r.Context(), page, pageSize, minAge, maxAge)
+	id := chi.URLParam(r, "id")
+	uuidID, err := uuid.Parse(id)
 	if err != nil {
-		log.Error("fail get all users", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
+		log.Error("invalid UUID format", sl.Err(err))
+		api.RenderProblem(w, r, api.ErrInvalidUUID)
 		return
 	}
-	logParams := []any{
-		slog.Uint64("page", uint64(page)),
-		slog.Uint64("page_size", uint64(pageSize)),
+
+	enrichment, err := h.service.GetUserEnrichment(r.Context(), uuidID)
+	if err != nil {
+		log.Error("fail get user enrichment", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
 	}
+	log.Info("get user enrichment success")
 
-	if minAge != nil {
-		logParams = append(logParams, slog.Int("min_age", *minAge))
+	render.JSON(w, r, GetUserEnrichmentResponse{
+		Response:   api.OK(),
+		UserID:     uuidID,
+		Enrichment: enrichment,
+	})
+}
+
+// @Tags user
+// @Description get the enrichment history of a user, newest first
+// @Param id path string true "user id"
+// @Param page query int false "page number" default(1) minimum(1)
+// @Param page_size query int false "items per page" default(10) minimum(1) maximum(100)
+// @Accept json
+// @Produce json
+// @Success 200 {object}  GetUserEnrichmentHistoryResponse
+// @Failure 400,404,422 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
+// @Router /users/{id}/enrichment/history [get]
+func (h *Handler) GetUserEnrichmentHistory(w http.ResponseWriter, r *http.Request) {
+	const op = "user.Handler.GetUserEnrichmentHistory"
+	log := h.Log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	id := chi.URLParam(r, "id")
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		log.Error("invalid UUID format", sl.Err(err))
+		api.RenderProblem(w, r, api.ErrInvalidUUID)
+		return
 	}
 
-	if maxAge != nil {
-		logParams = append(logParams, slog.Int("max_age", *maxAge))
+	pagination, err := parsePaginationQuery(r.URL.Query())
+	if err != nil {
+		log.Error("failed to parse query parameters", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
 	}
+	if err := api.Validate.Struct(pagination); err != nil {
+		log.Error("invalid query parameters", sl.Err(err))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrValidation, err))
+		return
+	}
+	page, pageSize := pagination.Page, pagination.PageSize
 
-	log.Info("get all users success", logParams...)
+	history, err := h.service.GetUserEnrichmentHistory(r.Context(), uuidID, page, pageSize)
+	if err != nil {
+		log.Error("fail get user enrichment history", sl.Err(err))
+		api.RenderProblem(w, r, err)
+		return
+	}
+	log.Info("get user enrichment history success",
+		slog.Uint64("page", uint64(page)),
+		slog.Uint64("page_size", uint64(pageSize)),
+	)
 
-	render.JSON(w, r, GetAllUsersResponse{
+	render.JSON(w, r, GetUserEnrichmentHistoryResponse{
 		Response:     api.OK(),
-		Users:        users,
+		UserID:       uuidID,
+		History:      history,
 		Page:         page,
 		ItemsPerPage: pageSize,
 	})
@@ -184,8 +329,8 @@ func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Success 200 {object}  DeleteUserResponse
-// @Failure 400,404 {object}  api.Response
-// @Failure 500 {object}  api.Response
+// @Failure 400,404 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
 // @Router /users/{id} [delete]
 func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	const op = "user.Handler.DeleteUser"
@@ -198,21 +343,21 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		log.Error("invalid UUID format", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid UUID format"))
+		api.RenderProblem(w, r, api.ErrInvalidUUID)
 		return
 	}
 
 	err = h.service.DeleteUserByID(r.Context(), uuidID)
 	if err != nil {
 		log.Error("fail get user", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
+		api.RenderProblem(w, r, err)
 		return
 	}
 	log.Info("delete user success")
 
 	render.JSON(w, r, DeleteUserResponse{
 		Response: api.OK(),
-		Ok:       "success deleted",
+		Ok:       api.T(r, api.MsgUserDeleted),
 	})
 }
 
@@ -223,8 +368,8 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param input body UpdateUserRequest true "update body"
 // @Success 200 {object} UpdateUserResponse
-// @Failure 400,404 {object} api.Response
-// @Failure 500 {object} api.Response
+// @Failure 400,404,409,422 {object}  api.ProblemDetails
+// @Failure 500 {object}  api.ProblemDetails
 // @Router /users/{id} [patch]
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	const op = "user.Handler.UpdateUser"
@@ -237,35 +382,43 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	uuidID, err := uuid.Parse(id)
 	if err != nil {
 		log.Error("invalid UUID format", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid UUID format"))
+		api.RenderProblem(w, r, api.ErrInvalidUUID)
 		return
 	}
 
 	var req UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Error("failed to decode request body", slog.Any("err", err))
-		render.JSON(w, r, api.Error("Ошибка при валидации тела"))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrBadRequest, err))
 		return
 	}
 
 	log.Info("request body decoded", slog.Any("request", req))
 
-	if err := validator.New().Struct(req); err != nil {
+	if err := api.Validate.Struct(req); err != nil {
 		log.Error("invalid request", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
+		api.RenderProblem(w, r, fmt.Errorf("%w: %w", api.ErrValidation, err))
 		return
 	}
 
-	if err := h.service.UpdateUser(r.Context(), uuidID, req.Name, req.Surname, req.Patronymic); err != nil {
+	res, err := h.service.UpdateUser(r.Context(), uuidID, req)
+	if err != nil {
 		log.Error("fail update user", sl.Err(err))
-		render.JSON(w, r, api.Error("invalid request"))
+		api.RenderProblem(w, r, err)
 		return
 	}
 
 	log.Info("update user success")
 
+	msg := api.MsgUserUpdated
+	if res.Status == EnrichmentPending {
+		msg = api.MsgUserUpdatedPending
+	}
 	render.JSON(w, r, UpdateUserResponse{
-		Response: api.OK(),
-		Ok:       "user updated successfully",
+		Response:         api.OK(),
+		Ok:               api.T(r, msg),
+		EnrichmentStatus: res.Status,
+		UnenrichedFields: res.Unenriched,
+		EnrichmentMode:   res.Mode,
 	})
 }
@@ -0,0 +1,38 @@
+package user
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func init() {
+	prometheus.MustRegister(reenrichedUsers)
+	prometheus.MustRegister(reenrichmentBacklog)
+	prometheus.MustRegister(reenrichmentRunDuration)
+}
+
+var reenrichedUsers = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "enrichment",
+		Subsystem: "reenrich",
+		Name:      "users_total",
+		Help:      "Users re-enriched by the scheduler by outcome (done, failed).",
+	},
+	[]string{"result"},
+)
+
+var reenrichmentBacklog = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "enrichment",
+		Subsystem: "reenrich",
+		Name:      "backlog",
+		Help:      "Users of the current scheduler run still waiting for re-enrichment.",
+	},
+)
+
+var reenrichmentRunDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Namespace: "enrichment",
+		Subsystem: "reenrich",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of scheduler runs.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	},
+)
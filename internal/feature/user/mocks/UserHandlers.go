@@ -5,10 +5,10 @@ package mocks
 import (
 	context "context"
 
+	enrich "github.com/Sanchir01/users-info/internal/enrich"
 	user "github.com/Sanchir01/users-info/internal/feature/user"
-	mock "github.com/stretchr/testify/mock"
-
 	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // UserHandlers is an autogenerated mock type for the UserHandlers type
@@ -16,22 +16,34 @@ type UserHandlers struct {
 	mock.Mock
 }
 
-// CreateUserService provides a mock function with given fields: name, surname, patronymic, ctx
-func (_m *UserHandlers) CreateUserService(name string, surname string, patronymic string, ctx context.Context) error {
-	ret := _m.Called(name, surname, patronymic, ctx)
+// CreateUserService provides a mock function with given fields: req, ctx
+func (_m *UserHandlers) CreateUserService(req user.CreateUserRequest, ctx context.Context) (uuid.UUID, error) {
+	ret := _m.Called(req, ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUserService")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, context.Context) error); ok {
-		r0 = rf(name, surname, patronymic, ctx)
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(user.CreateUserRequest, context.Context) (uuid.UUID, error)); ok {
+		return rf(req, ctx)
+	}
+	if rf, ok := ret.Get(0).(func(user.CreateUserRequest, context.Context) uuid.UUID); ok {
+		r0 = rf(req, ctx)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(user.CreateUserRequest, context.Context) error); ok {
+		r1 = rf(req, ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // DeleteUserByID provides a mock function with given fields: ctx, id
@@ -52,9 +64,9 @@ func (_m *UserHandlers) DeleteUserByID(ctx context.Context, id uuid.UUID) error
 	return r0
 }
 
-// GetAllUsers provides a mock function with given fields: ctx, page, pageSize, minAge, maxAge
-func (_m *UserHandlers) GetAllUsers(ctx context.Context, page uint, pageSize uint, minAge *int, maxAge *int) ([]*user.UserDB, error) {
-	ret := _m.Called(ctx, page, pageSize, minAge, maxAge)
+// GetAllUsers provides a mock function with given fields: ctx, page, pageSize, filter
+func (_m *UserHandlers) GetAllUsers(ctx context.Context, page uint, pageSize uint, filter user.UserFilter) ([]*user.UserDB, error) {
+	ret := _m.Called(ctx, page, pageSize, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllUsers")
@@ -62,19 +74,49 @@ func (_m *UserHandlers) GetAllUsers(ctx context.Context, page uint, pageSize uin
 
 	var r0 []*user.UserDB
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, *int, *int) ([]*user.UserDB, error)); ok {
-		return rf(ctx, page, pageSize, minAge, maxAge)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, user.UserFilter) ([]*user.UserDB, error)); ok {
+		return rf(ctx, page, pageSize, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, *int, *int) []*user.UserDB); ok {
-		r0 = rf(ctx, page, pageSize, minAge, maxAge)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, user.UserFilter) []*user.UserDB); ok {
+		r0 = rf(ctx, page, pageSize, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*user.UserDB)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, *int, *int) error); ok {
-		r1 = rf(ctx, page, pageSize, minAge, maxAge)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, user.UserFilter) error); ok {
+		r1 = rf(ctx, page, pageSize, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserHandlers) GetUserByID(ctx context.Context, id uuid.UUID) (*user.UserDB, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *user.UserDB
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*user.UserDB, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *user.UserDB); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.UserDB)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,22 +124,120 @@ func (_m *UserHandlers) GetAllUsers(ctx context.Context, page uint, pageSize uin
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: ctx, id, name, surname, patronymic
-func (_m *UserHandlers) UpdateUser(ctx context.Context, id uuid.UUID, name string, surname string, patronymic string) error {
-	ret := _m.Called(ctx, id, name, surname, patronymic)
+// GetUserEnrichment provides a mock function with given fields: ctx, id
+func (_m *UserHandlers) GetUserEnrichment(ctx context.Context, id uuid.UUID) ([]*user.EnrichmentDB, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserEnrichment")
+	}
+
+	var r0 []*user.EnrichmentDB
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*user.EnrichmentDB, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*user.EnrichmentDB); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.EnrichmentDB)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserEnrichmentHistory provides a mock function with given fields: ctx, id, page, pageSize
+func (_m *UserHandlers) GetUserEnrichmentHistory(ctx context.Context, id uuid.UUID, page uint, pageSize uint) ([]*user.EnrichmentHistoryDB, error) {
+	ret := _m.Called(ctx, id, page, pageSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserEnrichmentHistory")
+	}
+
+	var r0 []*user.EnrichmentHistoryDB
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, uint) ([]*user.EnrichmentHistoryDB, error)); ok {
+		return rf(ctx, id, page, pageSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, uint) []*user.EnrichmentHistoryDB); ok {
+		r0 = rf(ctx, id, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.EnrichmentHistoryDB)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint, uint) error); ok {
+		r1 = rf(ctx, id, page, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreviewEnrichment provides a mock function with given fields: ctx, req
+func (_m *UserHandlers) PreviewEnrichment(ctx context.Context, req user.PreviewEnrichmentRequest) (enrich.Result, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewEnrichment")
+	}
+
+	var r0 enrich.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, user.PreviewEnrichmentRequest) (enrich.Result, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, user.PreviewEnrichmentRequest) enrich.Result); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(enrich.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, user.PreviewEnrichmentRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, id, req
+func (_m *UserHandlers) UpdateUser(ctx context.Context, id uuid.UUID, req user.UpdateUserRequest) (user.UpdateUserResult, error) {
+	ret := _m.Called(ctx, id, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string, string) error); ok {
-		r0 = rf(ctx, id, name, surname, patronymic)
+	var r0 user.UpdateUserResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, user.UpdateUserRequest) (user.UpdateUserResult, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, user.UpdateUserRequest) user.UpdateUserResult); ok {
+		r0 = rf(ctx, id, req)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(user.UpdateUserResult)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, user.UpdateUserRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewUserHandlers creates a new instance of UserHandlers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
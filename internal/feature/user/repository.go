@@ -2,10 +2,15 @@ package user
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Sanchir01/users-info/internal/enrich"
 	"github.com/Sanchir01/users-info/internal/gender"
 	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
 	"github.com/jackc/pgx/v5"
@@ -16,9 +21,34 @@ type UpdateUserRequestDB struct {
 	Name        *string // pointer, чтобы можно было отличить "не передано" от пустой строки
 	Surname     *string
 	Patronymic  *string
+	CountryHint *string
 	Nationality *string
 	Age         *int
 	Gender      *gender.Gender
+	// флаги ручной установки значений, блокируют повторное обогащение поля
+	AgeManual         *bool
+	GenderManual      *bool
+	NationalityManual *bool
+}
+
+func (r *UpdateUserRequestDB) setManual(field enrich.Field, manual *bool) {
+	switch field {
+	case enrich.FieldAge:
+		r.AgeManual = manual
+	case enrich.FieldGender:
+		r.GenderManual = manual
+	case enrich.FieldNationality:
+		r.NationalityManual = manual
+	}
+}
+
+// EnrichmentUpdateDB is the outcome of an enrichment run. With a nil Result
+// only the status changes. Locked fields keep their current values.
+type EnrichmentUpdateDB struct {
+	Status  EnrichmentStatus
+	Result  *enrich.Result
+	Missing []enrich.Field
+	Locked  []enrich.Field
 }
 type Repository struct {
 	primaryDB *pgxpool.Pool
@@ -29,28 +59,85 @@ func NewRepository(primaryDB *pgxpool.Pool) *Repository {
 }
 
 func (r *Repository) CreateUserRepository(
-	name, surname, patronymic, nationality string,
-	age int, gender gender.Gender,
+	name, surname, patronymic, countryHint string,
 	tx pgx.Tx, ctx context.Context,
-) error {
+) (uuid.UUID, error) {
 	query, args, err := sq.Insert("users").
-		Columns("name", "surname", "patronymic", "nationality", "age", "gender").
-		Values(name, surname, patronymic, nationality, age, gender).
+		Columns("name", "surname", "patronymic", "country_hint", "enrichment_status").
+		Values(name, surname, patronymic, countryHint, EnrichmentPending).
+		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
-		return api.ErrQueryString
+		return uuid.Nil, api.ErrQueryString
 	}
 
-	_, err = tx.Exec(ctx, query, args...)
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+const userColumns = "id,name, surname,patronymic,created_at,updated_at,age,gender,nationality,enrichment_status,enriched_at,unenriched_fields,age_manual,gender_manual,nationality_manual,country_hint,enrichment_mode,enrichment_sources,version"
+
+// scanUser reads a row selected with userColumns.
+func scanUser(row pgx.Row) (*UserDB, error) {
+	var oneuserdb UserDB
+	if err := row.Scan(
+		&oneuserdb.ID,
+		&oneuserdb.Name,
+		&oneuserdb.Surname,
+		&oneuserdb.Patronymic,
+		&oneuserdb.CreatedAt,
+		&oneuserdb.UpdatedAt,
+		&oneuserdb.Age,
+		&oneuserdb.Gender,
+		&oneuserdb.Nationality,
+		&oneuserdb.EnrichmentStatus,
+		&oneuserdb.EnrichedAt,
+		&oneuserdb.UnenrichedFields,
+		&oneuserdb.AgeManual,
+		&oneuserdb.GenderManual,
+		&oneuserdb.NationalityManual,
+		&oneuserdb.CountryHint,
+		&oneuserdb.EnrichmentMode,
+		&oneuserdb.EnrichmentSources,
+		&oneuserdb.Version,
+	); err != nil {
+		return nil, err
+	}
+	return &oneuserdb, nil
+}
+
+func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (*UserDB, error) {
+	conn, err := r.primaryDB.Acquire(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer conn.Release()
 
-	return nil
+	query, args, err := sq.Select(userColumns).
+		From("public.users").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, api.ErrQueryString
+	}
+
+	user, err := scanUser(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, api.ErrNotFoundById
+		}
+		return nil, err
+	}
+	return user, nil
 }
 
-func (r *Repository) GetAllUsers(ctx context.Context, pageSize, pageNumber uint, minAge, maxAge *int) ([]*UserDB, error) {
+func (r *Repository) GetAllUsers(ctx context.Context, pageSize, pageNumber uint, filter UserFilter) ([]*UserDB, error) {
 	conn, err := r.primaryDB.Acquire(ctx)
 	if err != nil {
 		return nil, err
@@ -63,26 +150,21 @@ func (r *Repository) GetAllUsers(ctx context.Context, pageSize, pageNumber uint,
 	} else {
 		offset = 0
 	}
-	
+
 	// Start building the query
-	queryBuilder := sq.Select("id,name, surname,patronymic,created_at,updated_at,age,gender,nationality,version").
+	queryBuilder := sq.Select(userColumns).
 		From("public.users")
-	
-	// Add age filters if provided
-	if minAge != nil {
-		queryBuilder = queryBuilder.Where(sq.GtOrEq{"age": *minAge})
-	}
-	
-	if maxAge != nil {
-		queryBuilder = queryBuilder.Where(sq.LtOrEq{"age": *maxAge})
+
+	if conditions := userFilterConditions(filter); len(conditions) > 0 {
+		queryBuilder = queryBuilder.Where(conditions)
 	}
-	
+
 	// Add pagination
 	queryBuilder = queryBuilder.
 		PlaceholderFormat(sq.Dollar).
 		Limit(uint64(pageSize)).
 		Offset(uint64(offset))
-	
+
 	// Generate SQL
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -96,22 +178,11 @@ func (r *Repository) GetAllUsers(ctx context.Context, pageSize, pageNumber uint,
 	var users []*UserDB
 
 	for rows.Next() {
-		var oneuserdb UserDB
-		if err := rows.Scan(
-			&oneuserdb.ID,
-			&oneuserdb.Name,
-			&oneuserdb.Surname,
-			&oneuserdb.Patronymic,
-			&oneuserdb.CreatedAt,
-			&oneuserdb.UpdatedAt,
-			&oneuserdb.Age,
-			&oneuserdb.Gender,
-			&oneuserdb.Nationality,
-			&oneuserdb.Version,
-		); err != nil {
+		oneuserdb, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, &oneuserdb)
+		users = append(users, oneuserdb)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -121,6 +192,70 @@ func (r *Repository) GetAllUsers(ctx context.Context, pageSize, pageNumber uint,
 	return users, nil
 }
 
+// userFilterConditions translates filter to the WHERE conditions of users.
+func userFilterConditions(filter UserFilter) sq.And {
+	conditions := sq.And{}
+	if filter.MinAge != nil {
+		conditions = append(conditions, sq.GtOrEq{"age": *filter.MinAge})
+	}
+	if filter.MaxAge != nil {
+		conditions = append(conditions, sq.LtOrEq{"age": *filter.MaxAge})
+	}
+	if len(filter.Gender) > 0 {
+		conditions = append(conditions, sq.Eq{"gender": listOf[string](filter.Gender)})
+	}
+	if len(filter.Nationality) > 0 {
+		conditions = append(conditions, sq.Eq{"nationality": filter.Nationality})
+	}
+	if len(filter.EnrichmentStatus) > 0 {
+		conditions = append(conditions, sq.Eq{"enrichment_status": listOf[string](filter.EnrichmentStatus)})
+	}
+	for _, match := range []struct {
+		column string
+		value  string
+		prefix bool
+	}{
+		{"name", filter.Name, false},
+		{"name", filter.NamePrefix, true},
+		{"surname", filter.Surname, false},
+		{"surname", filter.SurnamePrefix, true},
+		{"patronymic", filter.Patronymic, false},
+		{"patronymic", filter.PatronymicPrefix, true},
+	} {
+		if match.value == "" {
+			continue
+		}
+		pattern := escapeLike(match.value)
+		if match.prefix {
+			pattern += "%"
+		}
+		conditions = append(conditions, sq.ILike{match.column: pattern})
+	}
+	for _, bound := range []struct {
+		column string
+		from   *time.Time
+		to     *time.Time
+	}{
+		{"created_at", filter.CreatedFrom, filter.CreatedTo},
+		{"updated_at", filter.UpdatedFrom, filter.UpdatedTo},
+	} {
+		if bound.from != nil {
+			conditions = append(conditions, sq.GtOrEq{bound.column: *bound.from})
+		}
+		if bound.to != nil {
+			conditions = append(conditions, sq.LtOrEq{bound.column: *bound.to})
+		}
+	}
+	return conditions
+}
+
+// escapeLike makes value match itself literally in a LIKE pattern.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *Repository) UpdateUser(
 	ctx context.Context,
 	id uuid.UUID,
@@ -138,6 +273,9 @@ func (r *Repository) UpdateUser(
 	if req.Patronymic != nil {
 		updateBuilder = updateBuilder.Set("patronymic", *req.Patronymic)
 	}
+	if req.CountryHint != nil {
+		updateBuilder = updateBuilder.Set("country_hint", *req.CountryHint)
+	}
 	if req.Nationality != nil {
 		updateBuilder = updateBuilder.Set("nationality", *req.Nationality)
 	}
@@ -147,6 +285,15 @@ func (r *Repository) UpdateUser(
 	if req.Gender != nil {
 		updateBuilder = updateBuilder.Set("gender", *req.Gender)
 	}
+	if req.AgeManual != nil {
+		updateBuilder = updateBuilder.Set("age_manual", *req.AgeManual)
+	}
+	if req.GenderManual != nil {
+		updateBuilder = updateBuilder.Set("gender_manual", *req.GenderManual)
+	}
+	if req.NationalityManual != nil {
+		updateBuilder = updateBuilder.Set("nationality_manual", *req.NationalityManual)
+	}
 
 	updateBuilder = updateBuilder.Set("updated_at", sq.Expr("NOW()"))
 
@@ -182,3 +329,449 @@ func (r *Repository) DeleteUserById(ctx context.Context, id uuid.UUID, tx pgx.Tx
 	}
 	return nil
 }
+
+// EnrichmentStateDB is what a user was last enriched from and with.
+type EnrichmentStateDB struct {
+	Name              string
+	Surname           string
+	Patronymic        string
+	CountryHint       string
+	Age               *int
+	Gender            *gender.Gender
+	Nationality       *string
+	EnrichmentStatus  EnrichmentStatus
+	EnrichmentMode    string
+	EnrichmentSources map[string]string
+}
+
+// query is the enrichment query for the stored inputs.
+func (s EnrichmentStateDB) query() enrich.Query {
+	return enrich.Query{Name: s.Name, Surname: s.Surname, Patronymic: s.Patronymic, CountryID: s.CountryHint}
+}
+
+// result rebuilds the stored enrichment values.
+func (s EnrichmentStateDB) result() enrich.Result {
+	res := enrich.Result{Age: s.Age, Mode: enrich.LocalizationMode(s.EnrichmentMode)}
+	if s.Gender != nil {
+		res.Gender = *s.Gender
+	}
+	if s.Nationality != nil {
+		res.Nationality = *s.Nationality
+	}
+	return res
+}
+
+// EnrichmentState reads the enrichment inputs and values of a user and locks
+// the row until tx ends.
+func (r *Repository) EnrichmentState(ctx context.Context, id uuid.UUID, tx pgx.Tx) (EnrichmentStateDB, error) {
+	query, args, err := sq.Select("name,surname,patronymic,country_hint,age,gender,nationality,enrichment_status,enrichment_mode,enrichment_sources").
+		From("users").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return EnrichmentStateDB{}, api.ErrQueryString
+	}
+
+	var state EnrichmentStateDB
+	if err := tx.QueryRow(ctx, query, args...).Scan(
+		&state.Name,
+		&state.Surname,
+		&state.Patronymic,
+		&state.CountryHint,
+		&state.Age,
+		&state.Gender,
+		&state.Nationality,
+		&state.EnrichmentStatus,
+		&state.EnrichmentMode,
+		&state.EnrichmentSources,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return EnrichmentStateDB{}, api.ErrNotFoundById
+		}
+		return EnrichmentStateDB{}, err
+	}
+	return state, nil
+}
+
+// LockedFields returns the manually set fields of a user and locks the row
+// until tx ends.
+func (r *Repository) LockedFields(ctx context.Context, id uuid.UUID, tx pgx.Tx) ([]enrich.Field, error) {
+	query, args, err := sq.Select("age_manual,gender_manual,nationality_manual").
+		From("users").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, api.ErrQueryString
+	}
+
+	var ageManual, genderManual, nationalityManual bool
+	if err := tx.QueryRow(ctx, query, args...).Scan(&ageManual, &genderManual, &nationalityManual); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, api.ErrNotFoundById
+		}
+		return nil, err
+	}
+
+	var locked []enrich.Field
+	if ageManual {
+		locked = append(locked, enrich.FieldAge)
+	}
+	if genderManual {
+		locked = append(locked, enrich.FieldGender)
+	}
+	if nationalityManual {
+		locked = append(locked, enrich.FieldNationality)
+	}
+	return locked, nil
+}
+
+// StaleUserDB is a user due for re-enrichment.
+type StaleUserDB struct {
+	ID          uuid.UUID
+	Name        string
+	Surname     string
+	Patronymic  string
+	CountryHint string
+}
+
+// ListStaleUsers returns up to limit users whose enrichment failed, finished
+// before staleBefore, or is still pending after a change made before
+// pendingBefore. The
+// least recently enriched users come first.
+func (r *Repository) ListStaleUsers(ctx context.Context, staleBefore, pendingBefore time.Time, limit uint64) ([]StaleUserDB, error) {
+	conn, err := r.primaryDB.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	query, args, err := sq.Select("id,name,surname,patronymic,country_hint").
+		From("public.users").
+		Where(sq.Or{
+			sq.Eq{"enrichment_status": EnrichmentFailed},
+			sq.And{
+				sq.NotEq{"enrichment_status": EnrichmentPending},
+				sq.Lt{"enriched_at": staleBefore},
+			},
+			sq.And{
+				sq.Eq{"enrichment_status": EnrichmentPending},
+				sq.Lt{"updated_at": pendingBefore},
+			},
+		}).
+		OrderBy("enriched_at ASC NULLS FIRST").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, api.ErrQueryString
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []StaleUserDB
+	for rows.Next() {
+		var one StaleUserDB
+		if err := rows.Scan(&one.ID, &one.Name, &one.Surname, &one.Patronymic, &one.CountryHint); err != nil {
+			return nil, err
+		}
+		users = append(users, one)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// enrichmentSourceManual marks fields whose value was set through UpdateUser.
+const enrichmentSourceManual = "manual"
+
+// SetEnrichment stores the enrichment outcome of a user. Missing values are
+// written as NULL (gender as unknown). A pending status keeps the time of the
+// last finished run.
+func (r *Repository) SetEnrichment(ctx context.Context, id uuid.UUID, upd EnrichmentUpdateDB, tx pgx.Tx) error {
+	updateBuilder := sq.Update("users").Where(sq.Eq{"id": id}).
+		Set("enrichment_status", upd.Status)
+	if upd.Status != EnrichmentPending {
+		updateBuilder = updateBuilder.Set("enriched_at", sq.Expr("NOW()"))
+	}
+
+	if res := upd.Result; res != nil {
+		if !slices.Contains(upd.Locked, enrich.FieldAge) {
+			updateBuilder = updateBuilder.Set("age", res.Age)
+		}
+		if !slices.Contains(upd.Locked, enrich.FieldGender) {
+			userGender := res.Gender
+			if userGender == "" {
+				userGender = gender.Unknown
+			}
+			updateBuilder = updateBuilder.Set("gender", userGender)
+		}
+		if !slices.Contains(upd.Locked, enrich.FieldNationality) {
+			var nationality *string
+			if res.Nationality != "" {
+				nationality = &res.Nationality
+			}
+			updateBuilder = updateBuilder.Set("nationality", nationality)
+		}
+		missing := make([]string, 0, len(upd.Missing))
+		for _, field := range upd.Missing {
+			missing = append(missing, string(field))
+		}
+		sources := make(map[string]string, len(res.Sources)+len(upd.Locked))
+		for field, source := range res.Sources {
+			sources[string(field)] = source
+		}
+		for _, field := range upd.Locked {
+			sources[string(field)] = enrichmentSourceManual
+		}
+		updateBuilder = updateBuilder.
+			Set("unenriched_fields", missing).
+			Set("enrichment_mode", res.Mode).
+			Set("enrichment_sources", sources)
+	}
+
+	query, args, err := updateBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+
+	cmdTag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return api.ErrNotFoundById
+	}
+	return nil
+}
+
+// SetEnrichmentState recomputes the status, the unenriched fields and the
+// sources of a user whose values changed without a new enrichment run.
+func (r *Repository) SetEnrichmentState(
+	ctx context.Context,
+	id uuid.UUID,
+	status EnrichmentStatus,
+	missing []enrich.Field,
+	sources map[string]string,
+	tx pgx.Tx,
+) error {
+	missingFields := make([]string, 0, len(missing))
+	for _, field := range missing {
+		missingFields = append(missingFields, string(field))
+	}
+	query, args, err := sq.Update("users").Where(sq.Eq{"id": id}).
+		Set("enrichment_status", status).
+		Set("unenriched_fields", missingFields).
+		Set("enrichment_sources", sources).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+
+	cmdTag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return api.ErrNotFoundById
+	}
+	return nil
+}
+
+// SaveEnrichment replaces the stored predictions of the user with the ones of
+// the latest run, so every row backs a current value.
+func (r *Repository) SaveEnrichment(ctx context.Context, userID uuid.UUID, predictions []enrich.Prediction, tx pgx.Tx) error {
+	query, args, err := sq.Delete("user_enrichment").
+		Where(sq.Eq{"user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+	if len(predictions) == 0 {
+		return nil
+	}
+
+	insertBuilder := sq.Insert("user_enrichment").
+		Columns("user_id", "provider", "input_name", "country_id", "value", "probability", "sample_count", "countries", "fetched_at")
+	for _, p := range predictions {
+		countries := p.Countries
+		if countries == nil {
+			countries = []enrich.CountryPrediction{}
+		}
+		insertBuilder = insertBuilder.Values(userID, p.Provider, p.Name, p.CountryID, p.Value, p.Probability, p.Count, countries, p.FetchedAt)
+	}
+	query, args, err = insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+// AppendEnrichmentHistory records the predictions as new history events.
+// History rows are never updated.
+func (r *Repository) AppendEnrichmentHistory(ctx context.Context, userID uuid.UUID, predictions []enrich.Prediction, tx pgx.Tx) error {
+	if len(predictions) == 0 {
+		return nil
+	}
+	insertBuilder := sq.Insert("user_enrichment_history").
+		Columns("user_id", "provider", "input_name", "country_id", "value", "probability", "sample_count", "countries", "fetched_at")
+	for _, p := range predictions {
+		countries := p.Countries
+		if countries == nil {
+			countries = []enrich.CountryPrediction{}
+		}
+		insertBuilder = insertBuilder.Values(userID, p.Provider, p.Name, p.CountryID, p.Value, p.Probability, p.Count, countries, p.FetchedAt)
+	}
+	query, args, err := insertBuilder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+
+	_, err = tx.Exec(ctx, query, args...)
+	return err
+}
+
+// userExists returns api.ErrNotFoundById when there is no user with id.
+func userExists(ctx context.Context, conn *pgxpool.Conn, id uuid.UUID) error {
+	query, args, err := sq.Select("1").
+		Prefix("SELECT EXISTS(").
+		From("users").
+		Where(sq.Eq{"id": id}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return api.ErrQueryString
+	}
+	var exists bool
+	if err := conn.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return api.ErrNotFoundById
+	}
+	return nil
+}
+
+func (r *Repository) GetUserEnrichment(ctx context.Context, userID uuid.UUID) ([]*EnrichmentDB, error) {
+	conn, err := r.primaryDB.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err := userExists(ctx, conn, userID); err != nil {
+		return nil, err
+	}
+
+	query, args, err := sq.Select("provider,input_name,country_id,value,probability,sample_count,countries,fetched_at").
+		From("public.user_enrichment").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("provider").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, api.ErrQueryString
+	}
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enrichment := make([]*EnrichmentDB, 0)
+	for rows.Next() {
+		var one EnrichmentDB
+		if err := rows.Scan(
+			&one.Provider,
+			&one.InputName,
+			&one.CountryID,
+			&one.Value,
+			&one.Probability,
+			&one.Count,
+			&one.Countries,
+			&one.FetchedAt,
+		); err != nil {
+			return nil, err
+		}
+		enrichment = append(enrichment, &one)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return enrichment, nil
+}
+
+func (r *Repository) GetUserEnrichmentHistory(ctx context.Context, userID uuid.UUID, pageSize, pageNumber uint) ([]*EnrichmentHistoryDB, error) {
+	conn, err := r.primaryDB.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	if err := userExists(ctx, conn, userID); err != nil {
+		return nil, err
+	}
+
+	var offset uint
+	if pageNumber >= 1 {
+		offset = (pageNumber - 1) * pageSize
+	}
+	query, args, err := sq.Select("id,provider,input_name,country_id,value,probability,sample_count,countries,fetched_at,recorded_at").
+		From("public.user_enrichment_history").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("recorded_at DESC", "id DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, api.ErrQueryString
+	}
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]*EnrichmentHistoryDB, 0)
+	for rows.Next() {
+		var one EnrichmentHistoryDB
+		if err := rows.Scan(
+			&one.ID,
+			&one.Provider,
+			&one.InputName,
+			&one.CountryID,
+			&one.Value,
+			&one.Probability,
+			&one.Count,
+			&one.Countries,
+			&one.FetchedAt,
+			&one.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, &one)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+)
+
+// ReenrichmentSettings configures the periodic re-enrichment of users.
+type ReenrichmentSettings struct {
+	Interval time.Duration
+	// StaleAfter is the age of an enrichment after which it is refreshed.
+	StaleAfter time.Duration
+	// Concurrency is the number of users enriched at a time. Concurrent
+	// lookups are coalesced into provider batches, so it should be at least
+	// the batch size.
+	Concurrency int
+	// BatchSize caps the number of users refreshed per run.
+	BatchSize int
+}
+
+// RunReenrichment refreshes stale, failed and lost pending enrichments every
+// settings.Interval until ctx is cancelled. Users pending for longer than an
+// interval are considered dropped from the full queue. Fresh lookups bypass
+// the provider cache.
+func (s *Service) RunReenrichment(ctx context.Context, settings ReenrichmentSettings) {
+	if settings.Interval <= 0 {
+		slog.Error("некорректный интервал повторного обогащения", slog.Duration("interval", settings.Interval))
+		return
+	}
+	ticker := time.NewTicker(settings.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.reenrichStale(ctx, settings); err != nil {
+				slog.Error("ошибка повторного обогащения пользователей", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+func (s *Service) reenrichStale(ctx context.Context, settings ReenrichmentSettings) error {
+	start := time.Now()
+	defer func() {
+		reenrichmentRunDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	users, err := s.repo.ListStaleUsers(ctx,
+		start.Add(-settings.StaleAfter), start.Add(-settings.Interval), uint64(max(settings.BatchSize, 1)))
+	if err != nil {
+		return err
+	}
+	reenrichmentBacklog.Set(float64(len(users)))
+	if len(users) == 0 {
+		return nil
+	}
+	slog.Info("повторное обогащение пользователей", slog.Int("count", len(users)))
+
+	ctx = enrich.WithoutCache(ctx)
+	sem := make(chan struct{}, max(settings.Concurrency, 1))
+	var wg sync.WaitGroup
+	for _, u := range users {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			q := enrich.Query{Name: u.Name, Surname: u.Surname, Patronymic: u.Patronymic, CountryID: u.CountryHint}
+			if err := s.enrichUser(ctx, u.ID, q); err != nil {
+				reenrichedUsers.WithLabelValues("failed").Inc()
+				slog.Warn("не удалось повторно обогатить пользователя",
+					slog.String("user_id", u.ID.String()),
+					slog.String("error", err.Error()))
+			} else {
+				reenrichedUsers.WithLabelValues("done").Inc()
+			}
+			reenrichmentBacklog.Dec()
+		}()
+	}
+	wg.Wait()
+	return nil
+}
@@ -2,58 +2,47 @@ package user
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log/slog"
-	"net/http"
-	"time"
+	"slices"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
-	genderctx "github.com/Sanchir01/users-info/internal/gender"
+	"github.com/Sanchir01/users-info/internal/enrich"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var (
-	genderUrl      = "https://api.genderize.io/?name=%s"
-	nationalityUrl = "https://api.nationalize.io/?name=%s"
-	ageUrl         = "https://api.agify.io/?name=%s"
-)
-
 type Service struct {
-	repo       *Repository
-	primaryDB  *pgxpool.Pool
-	httpClient *http.Client
+	repo      *Repository
+	primaryDB *pgxpool.Pool
+	enricher  enrich.Enricher
+	jobs      chan enrichmentJob
 }
 
-func NewService(repo *Repository, primaryDB *pgxpool.Pool) *Service {
-	return &Service{repo: repo, primaryDB: primaryDB, httpClient: &http.Client{Timeout: 5 * time.Second}}
+func NewService(repo *Repository, primaryDB *pgxpool.Pool, enricher enrich.Enricher, queueSize int) *Service {
+	return &Service{
+		repo:      repo,
+		primaryDB: primaryDB,
+		enricher:  enricher,
+		jobs:      make(chan enrichmentJob, queueSize),
+	}
 }
 
 func (s *Service) CreateUserService(
-	name, surname, patronymic string,
+	req CreateUserRequest,
 	ctx context.Context,
-) error {
+) (uuid.UUID, error) {
 	conn, err := s.primaryDB.Acquire(ctx)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 	defer conn.Release()
 	tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return err
-	}
-	type result struct {
-		value interface{}
-		err   error
+		return uuid.Nil, err
 	}
-
-	genderCh := make(chan result, 1)
-	ageCh := make(chan result, 1)
-	nationalityCh := make(chan result, 1)
 	defer func() {
 		if err != nil {
 			rollbackErr := tx.Rollback(ctx)
@@ -64,65 +53,56 @@ func (s *Service) CreateUserService(
 		}
 	}()
 
-	go func() {
-		gender, err := s.getGenderUser(ctx, name)
-		genderCh <- result{value: gender, err: err}
-	}()
-	go func() {
-		age, err := s.getAgeUser(ctx, name)
-		ageCh <- result{value: age, err: err}
-	}()
-	go func() {
-		nationality, err := s.getNationalityUser(ctx, name)
-		nationalityCh <- result{value: nationality, err: err}
-	}()
-
-	genderRes := <-genderCh
-	ageRes := <-ageCh
-	nationalityRes := <-nationalityCh
-
-	if genderRes.err != nil {
-		slog.Error("ошибка получения пола пользователя:", genderRes.err.Error())
-		return genderRes.err
-	}
-	if ageRes.err != nil {
-		slog.Error("ошибка получения возраста пользователя:", ageRes.err.Error())
-		return ageRes.err
-	}
-	if nationalityRes.err != nil {
-		slog.Error("ошибка получения национальности пользователя:", nationalityRes.err.Error())
-		return nationalityRes.err
-	}
-
-	genderuser, ok := genderRes.value.(genderctx.Gender)
-	if !ok {
-		return fmt.Errorf("не удалось привести genderuser к string")
-	}
-	ageuser, ok := ageRes.value.(int)
-	if !ok {
-		return fmt.Errorf("не удалось привести ageuser к int")
-	}
-	nationalityuser, ok := nationalityRes.value.(string)
-	if !ok {
-		return fmt.Errorf("не удалось привести nationalityuser к string")
+	id, err := s.repo.CreateUserRepository(req.Name, req.Surname, req.Patronymic, req.CountryHint, tx, ctx)
+	if err != nil {
+		return uuid.Nil, err
 	}
-	if err := s.repo.CreateUserRepository(name, surname, patronymic, nationalityuser, ageuser, genderuser, tx, ctx); err != nil {
-		return err
+	if err = tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return err
-	}
-	return nil
+	s.enqueueEnrichment(id, req.query())
+	return id, nil
 }
-func (s *Service) GetAllUsers(ctx context.Context, page, pageSize uint, minAge, maxAge *int) ([]*UserDB, error) {
-	users, err := s.repo.GetAllUsers(ctx, pageSize, page, minAge, maxAge)
+func (s *Service) GetAllUsers(ctx context.Context, page, pageSize uint, filter UserFilter) ([]*UserDB, error) {
+	users, err := s.repo.GetAllUsers(ctx, pageSize, page, filter)
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
+func (s *Service) GetUserByID(ctx context.Context, id uuid.UUID) (*UserDB, error) {
+	return s.repo.GetUserByID(ctx, id)
+}
+
+func (s *Service) GetUserEnrichment(ctx context.Context, id uuid.UUID) ([]*EnrichmentDB, error) {
+	return s.repo.GetUserEnrichment(ctx, id)
+}
+
+func (s *Service) GetUserEnrichmentHistory(ctx context.Context, id uuid.UUID, page, pageSize uint) ([]*EnrichmentHistoryDB, error) {
+	return s.repo.GetUserEnrichmentHistory(ctx, id, pageSize, page)
+}
+
+// PreviewEnrichment enriches a person like CreateUserService would, without
+// storing anything.
+func (s *Service) PreviewEnrichment(ctx context.Context, req PreviewEnrichmentRequest) (enrich.Result, error) {
+	res, err := s.enricher.Enrich(ctx, req.query())
+	if err != nil {
+		return enrich.Result{}, enrichmentError(err)
+	}
+	return res, nil
+}
+
+// enrichmentError tells the API whether the providers are unavailable or
+// answered with an error.
+func enrichmentError(err error) error {
+	if enrich.Unavailable(err) {
+		return fmt.Errorf("%w: %w", api.ErrUnavailable, err)
+	}
+	return fmt.Errorf("%w: %w", api.ErrUpstream, err)
+}
+
 func (s *Service) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
 	conn, err := s.primaryDB.Acquire(ctx)
 	if err != nil {
@@ -151,10 +131,19 @@ func (s *Service) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (s *Service) UpdateUser(ctx context.Context, id uuid.UUID, name, surname, patronymic string) error {
+// UpdateUser renames the user and applies manual overrides. A changed name is
+// enriched by the workers after the commit, so the providers are never called
+// inside the transaction; otherwise the stored values are kept.
+func (s *Service) UpdateUser(ctx context.Context, id uuid.UUID, upd UpdateUserRequest) (res UpdateUserResult, err error) {
+	for _, field := range upd.overridden() {
+		if slices.Contains(upd.Unlock, field) {
+			return UpdateUserResult{}, api.ErrOverrideConflict
+		}
+	}
+
 	conn, err := s.primaryDB.Acquire(ctx)
 	if err != nil {
-		return err
+		return UpdateUserResult{}, err
 	}
 	defer conn.Release()
 	tx, err := conn.BeginTx(ctx, pgx.TxOptions{})
@@ -168,175 +157,53 @@ func (s *Service) UpdateUser(ctx context.Context, id uuid.UUID, name, surname, p
 		}
 	}()
 	if err != nil {
-		return err
-	}
-	type result struct {
-		value interface{}
-		err   error
-	}
-
-	genderCh := make(chan result, 1)
-	ageCh := make(chan result, 1)
-	nationalityCh := make(chan result, 1)
-	defer func() {
-		if err != nil {
-			rollbackErr := tx.Rollback(ctx)
-			if rollbackErr != nil {
-				err = errors.Join(err, rollbackErr)
-				return
-			}
-		}
-	}()
-
-	go func() {
-		gender, err := s.getGenderUser(ctx, name)
-		genderCh <- result{value: gender, err: err}
-	}()
-	go func() {
-		age, err := s.getAgeUser(ctx, name)
-		ageCh <- result{value: age, err: err}
-	}()
-	go func() {
-		nationality, err := s.getNationalityUser(ctx, name)
-		nationalityCh <- result{value: nationality, err: err}
-	}()
-
-	genderRes := <-genderCh
-	ageRes := <-ageCh
-	nationalityRes := <-nationalityCh
-
-	if genderRes.err != nil {
-		slog.Error("ошибка получения пола пользователя:", genderRes.err.Error())
-		return genderRes.err
-	}
-	if ageRes.err != nil {
-		slog.Error("ошибка получения возраста пользователя:", ageRes.err.Error())
-		return ageRes.err
-	}
-	if nationalityRes.err != nil {
-		slog.Error("ошибка получения национальности пользователя:", nationalityRes.err.Error())
-		return nationalityRes.err
-	}
-
-	genderuser, ok := genderRes.value.(genderctx.Gender)
-	if !ok {
-		return fmt.Errorf("не удалось привести genderuser к string")
-	}
-	ageuser, ok := ageRes.value.(int)
-	if !ok {
-		return fmt.Errorf("не удалось привести ageuser к int")
-	}
-	nationalityuser, ok := nationalityRes.value.(string)
-	if !ok {
-		return fmt.Errorf("не удалось привести nationalityuser к string")
-	}
-	req := UpdateUserRequestDB{
-		Name:        &name,
-		Surname:     &surname,
-		Patronymic:  &patronymic,
-		Nationality: &nationalityuser,
-		Age:         &ageuser,
-		Gender:      &genderuser,
-	}
-	if err := s.repo.UpdateUser(ctx, id, req, tx); err != nil {
-		return err
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return err
-	}
-	return nil
-}
-
-func (s *Service) getGenderUser(ctx context.Context, name string) (genderctx.Gender, error) {
-	urlgender := fmt.Sprintf(genderUrl, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlgender, nil)
-	if err != nil {
-		return genderctx.Unknown, err
-	}
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return genderctx.Unknown, err
+		return UpdateUserResult{}, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("ошибка получения данных с genderize:", resp.Status)
-		return genderctx.Unknown, fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-	var data GenderizeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		slog.Error("ошибка парсинга:", err.Error())
-		return genderctx.Unknown, err
-	}
-	if err := validator.New().Struct(&data); err != nil {
-		slog.Error("ошибка валидации данных:", err.Error())
-		return genderctx.Unknown, fmt.Errorf("validation error: %w", err)
-	}
-	switch data.Gender {
-	case genderctx.GenderMale:
-		return genderctx.GenderMale, nil
-	case genderctx.GenderFemale:
-		return genderctx.GenderFemale, nil
-	default:
-		return genderctx.Unknown, nil
-	}
-
-}
 
-func (s *Service) getAgeUser(ctx context.Context, name string) (int, error) {
-	urlgender := fmt.Sprintf(ageUrl, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlgender, nil)
+	state, err := s.repo.EnrichmentState(ctx, id, tx)
 	if err != nil {
-		return 0, err
+		return UpdateUserResult{}, err
 	}
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("ошибка получения данных с genderize:", resp.Status)
-		return 0, fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-	var data UserAgeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		slog.Error("ошибка парсинга:", err.Error())
-		return 0, err
-	}
-	if err := validator.New().Struct(&data); err != nil {
-		slog.Error("ошибка валидации данных:", err.Error())
-		return 0, fmt.Errorf("validation error: %w", err)
+	req := UpdateUserRequestDB{
+		Name:        &upd.Name,
+		Surname:     &upd.Surname,
+		Patronymic:  &upd.Patronymic,
+		CountryHint: upd.CountryHint,
+		Age:         upd.Age,
+		Gender:      upd.Gender,
+		Nationality: upd.Nationality,
+	}
+	manual, unlocked := true, false
+	for _, field := range upd.overridden() {
+		req.setManual(field, &manual)
+	}
+	for _, field := range upd.Unlock {
+		req.setManual(field, &unlocked)
+	}
+	if err = s.repo.UpdateUser(ctx, id, req, tx); err != nil {
+		return UpdateUserResult{}, err
+	}
+
+	reenrich := upd.needsEnrichment(state)
+	if reenrich {
+		// the providers are asked by the workers once the update is committed
+		err = s.repo.SetEnrichment(ctx, id, EnrichmentUpdateDB{Status: EnrichmentPending}, tx)
+		res = UpdateUserResult{Status: EnrichmentPending}
+	} else {
+		stored := state.result()
+		var missing []enrich.Field
+		missing, err = s.refreshEnrichment(ctx, id, stored, state.EnrichmentSources, tx)
+		res = UpdateUserResult{Status: enrichmentStatus(missing), Unenriched: missing, Mode: stored.Mode}
 	}
-	return data.Age, nil
-
-}
-
-func (s *Service) getNationalityUser(ctx context.Context, name string) (string, error) {
-	urlgender := fmt.Sprintf(nationalityUrl, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlgender, nil)
 	if err != nil {
-		return "", err
+		return UpdateUserResult{}, err
 	}
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		slog.Error("ошибка получения данных с genderize:", resp.Status)
-		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-	var data NationalizeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		slog.Error("ошибка парсинга:", err.Error())
-		return "", err
+	if err = tx.Commit(ctx); err != nil {
+		return UpdateUserResult{}, err
 	}
-	if err := validator.New().Struct(&data); err != nil {
-		slog.Error("ошибка валидации данных:", err.Error())
-		return "", fmt.Errorf("validation error: %w", err)
+	if reenrich {
+		s.enqueueEnrichment(id, upd.query(state))
 	}
-	return data.Country[0].CountryID, nil
+	return res, nil
 }
@@ -0,0 +1,168 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"sync"
+
+	"github.com/Sanchir01/users-info/internal/enrich"
+	api "github.com/Sanchir01/users-info/pkg/lib/api/response"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type enrichmentJob struct {
+	id    uuid.UUID
+	query enrich.Query
+}
+
+// enqueueEnrichment schedules background enrichment of a freshly created user.
+// When the queue is full the user stays pending and is picked up later.
+func (s *Service) enqueueEnrichment(id uuid.UUID, q enrich.Query) {
+	select {
+	case s.jobs <- enrichmentJob{id: id, query: q}:
+	default:
+		slog.Warn("очередь обогащения переполнена, пользователь остаётся в статусе pending",
+			slog.String("user_id", id.String()))
+	}
+}
+
+// RunEnrichmentWorkers processes queued enrichment jobs with the given number
+// of workers and blocks until ctx is cancelled.
+func (s *Service) RunEnrichmentWorkers(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-s.jobs:
+					if err := s.enrichUser(ctx, job.id, job.query); err != nil {
+						slog.Error("ошибка обогащения данных пользователя",
+							slog.String("user_id", job.id.String()),
+							slog.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// enrichUser fetches age, gender and nationality for the user and stores them
+// together with the resulting enrichment status.
+func (s *Service) enrichUser(ctx context.Context, id uuid.UUID, q enrich.Query) error {
+	enriched, enrichErr := s.enricher.Enrich(ctx, q)
+
+	if err := s.saveEnrichment(ctx, id, q, enriched, enrichErr); err != nil {
+		return errors.Join(enrichErr, err)
+	}
+	return enrichErr
+}
+
+// saveEnrichment stores the result of q unless the user was renamed, got
+// another country hint or was deleted while q was enriched: the result no
+// longer applies and a newer run takes care of the user.
+func (s *Service) saveEnrichment(
+	ctx context.Context,
+	id uuid.UUID,
+	q enrich.Query,
+	enriched enrich.Result,
+	enrichErr error,
+) (err error) {
+	tx, err := s.primaryDB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+
+	state, err := s.repo.EnrichmentState(ctx, id, tx)
+	if errors.Is(err, api.ErrNotFoundById) || (err == nil && state.query() != q) {
+		slog.Info("результат обогащения устарел и не сохранён",
+			slog.String("user_id", id.String()))
+		return tx.Rollback(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.writeEnrichment(ctx, id, enriched, enrichErr, tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// writeEnrichment stores the outcome of an enrichment run within tx and
+// returns the fields left unenriched. A failed run only changes the status
+// and keeps the previously enriched values, manually locked fields are never
+// overwritten.
+func (s *Service) writeEnrichment(
+	ctx context.Context,
+	id uuid.UUID,
+	enriched enrich.Result,
+	enrichErr error,
+	tx pgx.Tx,
+) ([]enrich.Field, error) {
+	if enrichErr != nil {
+		return nil, s.repo.SetEnrichment(ctx, id, EnrichmentUpdateDB{Status: EnrichmentFailed}, tx)
+	}
+	locked, err := s.repo.LockedFields(ctx, id, tx)
+	if err != nil {
+		return nil, err
+	}
+	missing := unenrichedFields(enriched, locked)
+	upd := EnrichmentUpdateDB{
+		Status:  enrichmentStatus(missing),
+		Result:  &enriched,
+		Missing: missing,
+		Locked:  locked,
+	}
+	if err := s.repo.SetEnrichment(ctx, id, upd, tx); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveEnrichment(ctx, id, enriched.Predictions, tx); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AppendEnrichmentHistory(ctx, id, enriched.Predictions, tx); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// refreshEnrichment keeps the stored enrichment and only recomputes the
+// status, the unenriched fields and the sources after manual changes.
+func (s *Service) refreshEnrichment(
+	ctx context.Context,
+	id uuid.UUID,
+	stored enrich.Result,
+	sources map[string]string,
+	tx pgx.Tx,
+) ([]enrich.Field, error) {
+	locked, err := s.repo.LockedFields(ctx, id, tx)
+	if err != nil {
+		return nil, err
+	}
+	missing := unenrichedFields(stored, locked)
+	sources = maps.Clone(sources)
+	if sources == nil {
+		sources = make(map[string]string)
+	}
+	for _, field := range locked {
+		sources[string(field)] = enrichmentSourceManual
+	}
+	if err := s.repo.SetEnrichmentState(ctx, id, enrichmentStatus(missing), missing, sources, tx); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
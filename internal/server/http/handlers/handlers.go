@@ -18,9 +18,16 @@ func StartHTTTPHandlers(handlers *app.Handlers) http.Handler {
 	router.Route("/apiv1", func(r chi.Router) {
 		r.Route("/users", func(r chi.Router) {
 			r.Get("/", handlers.UserHandler.GetAllUsers)
+			r.Get("/{id}", handlers.UserHandler.GetUserByID)
 			r.Delete("/{id}", handlers.UserHandler.DeleteUser)
 			r.Post("/create", handlers.UserHandler.CreateUser)
 			r.Patch("/{id}", handlers.UserHandler.UpdateUser)
+			r.Get("/{id}/enrichment", handlers.UserHandler.GetUserEnrichment)
+			r.Get("/{id}/enrichment/history", handlers.UserHandler.GetUserEnrichmentHistory)
+		})
+		r.Post("/enrich/preview", handlers.UserHandler.PreviewEnrichment)
+		r.Route("/admin", func(r chi.Router) {
+			r.Get("/enrichment/quota", handlers.AdminHandler.GetQuota)
 		})
 	})
 	router.Get("/swagger/*", httpSwagger.Handler(
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE enrichment_status_enum AS ENUM ('pending', 'done', 'failed');
+ALTER TABLE users
+    ADD COLUMN enrichment_status enrichment_status_enum NOT NULL DEFAULT 'pending',
+    ADD COLUMN enriched_at TIMESTAMP,
+    ALTER COLUMN age DROP NOT NULL,
+    ALTER COLUMN gender DROP NOT NULL,
+    ALTER COLUMN nationality DROP NOT NULL;
+UPDATE users SET enrichment_status = 'done', enriched_at = updated_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- users still waiting for enrichment cannot satisfy NOT NULL again, refuse to
+-- roll back rather than delete them
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE age IS NULL OR gender IS NULL OR nationality IS NULL) THEN
+        RAISE EXCEPTION 'cannot roll back: users without age, gender or nationality exist';
+    END IF;
+END $$;
+ALTER TABLE users
+    DROP COLUMN enrichment_status,
+    DROP COLUMN enriched_at,
+    ALTER COLUMN age SET NOT NULL,
+    ALTER COLUMN gender SET NOT NULL,
+    ALTER COLUMN nationality SET NOT NULL;
+DROP TYPE enrichment_status_enum;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_enrichment(
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    provider TEXT NOT NULL,
+                                    input_name TEXT NOT NULL,
+                                    value TEXT NOT NULL,
+                                    probability DOUBLE PRECISION,
+                                    sample_count INT NOT NULL DEFAULT 0,
+                                    countries JSONB NOT NULL DEFAULT '[]',
+                                    fetched_at TIMESTAMP NOT NULL,
+                                    PRIMARY KEY (user_id, provider)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_enrichment;
+-- +goose StatementEnd
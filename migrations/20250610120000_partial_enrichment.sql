@@ -0,0 +1,9 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE gender_enum ADD VALUE IF NOT EXISTS 'unknown';
+ALTER TYPE enrichment_status_enum ADD VALUE IF NOT EXISTS 'partial' BEFORE 'failed';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS unenriched_fields TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS unenriched_fields;
+SELECT 'enum values unknown and partial are kept, postgres cannot drop them';
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN age_manual BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN gender_manual BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN nationality_manual BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN age_manual,
+    DROP COLUMN gender_manual,
+    DROP COLUMN nationality_manual;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN country_hint TEXT NOT NULL DEFAULT '',
+    ADD COLUMN enrichment_mode TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_enrichment
+    ADD COLUMN country_id TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_enrichment
+    DROP COLUMN country_id;
+ALTER TABLE users
+    DROP COLUMN country_hint,
+    DROP COLUMN enrichment_mode;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_enrichment_history(
+                                    id BIGSERIAL PRIMARY KEY,
+                                    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                    provider TEXT NOT NULL,
+                                    input_name TEXT NOT NULL,
+                                    country_id TEXT NOT NULL DEFAULT '',
+                                    value TEXT NOT NULL,
+                                    probability DOUBLE PRECISION,
+                                    sample_count INT NOT NULL DEFAULT 0,
+                                    countries JSONB NOT NULL DEFAULT '[]',
+                                    fetched_at TIMESTAMP NOT NULL,
+                                    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS user_enrichment_history_user_id_idx
+    ON user_enrichment_history (user_id, recorded_at DESC);
+INSERT INTO user_enrichment_history (user_id, provider, input_name, country_id, value, probability, sample_count, countries, fetched_at, recorded_at)
+SELECT user_id, provider, input_name, country_id, value, probability, sample_count, countries, fetched_at, fetched_at
+FROM user_enrichment;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_enrichment_history;
+-- +goose StatementEnd
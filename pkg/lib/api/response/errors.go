@@ -1,8 +1,63 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
 
 var (
-	ErrQueryString  = errors.New("query not created, check your query string")
-	ErrNotFoundById = errors.New("not found by id")
+	ErrQueryString      = errors.New("query not created, check your query string")
+	ErrNotFoundById     = errors.New("not found by id")
+	ErrOverrideConflict = errors.New("field cannot be set and unlocked at the same time")
+
+	// ErrBadRequest marks requests that cannot be read: malformed JSON, a
+	// broken path or query parameter.
+	ErrBadRequest = errors.New("bad request")
+	// ErrInvalidUUID marks a malformed id in the request path.
+	ErrInvalidUUID = fmt.Errorf("%w: invalid UUID format", ErrBadRequest)
+	// ErrValidation marks well-formed requests with invalid values.
+	ErrValidation = errors.New("validation failed")
+	// ErrUpstream marks enrichment providers answering with an error or
+	// an unreadable body.
+	ErrUpstream = errors.New("enrichment provider failed")
+	// ErrUnavailable marks enrichment providers that are unreachable,
+	// overloaded, or not called because of an open circuit or spent quota.
+	ErrUnavailable = errors.New("enrichment provider is unavailable")
 )
+
+// ParamError marks an unreadable query parameter, it is a bad request.
+type ParamError struct {
+	Param string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("%s: invalid %s parameter", ErrBadRequest, e.Param)
+}
+
+func (e *ParamError) Unwrap() error {
+	return ErrBadRequest
+}
+
+// HTTPStatus maps err to the status code of its kind, 500 for unknown errors.
+func HTTPStatus(err error) int {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotFoundById):
+		return http.StatusNotFound
+	case errors.Is(err, ErrOverrideConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrValidation), errors.As(err, &validationErrs):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrUpstream):
+		return http.StatusBadGateway
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"bad request", ErrBadRequest, http.StatusBadRequest},
+		{"invalid uuid", ErrInvalidUUID, http.StatusBadRequest},
+		{"invalid parameter", &ParamError{Param: "page"}, http.StatusBadRequest},
+		{"wrapped decode error", fmt.Errorf("%w: %w", ErrBadRequest, errors.New("unexpected EOF")), http.StatusBadRequest},
+		{"not found", fmt.Errorf("get user: %w", ErrNotFoundById), http.StatusNotFound},
+		{"override conflict", ErrOverrideConflict, http.StatusConflict},
+		{"validation", ErrValidation, http.StatusUnprocessableEntity},
+		{"validator errors", validator.ValidationErrors{}, http.StatusUnprocessableEntity},
+		{"upstream", fmt.Errorf("%w: genderize: bad body", ErrUpstream), http.StatusBadGateway},
+		{"unavailable", fmt.Errorf("%w: circuit breaker is open", ErrUnavailable), http.StatusServiceUnavailable},
+		{"query string", ErrQueryString, http.StatusInternalServerError},
+		{"unknown", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.err); got != tt.want {
+			t.Errorf("%s: HTTPStatus = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
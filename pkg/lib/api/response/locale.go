@@ -0,0 +1,217 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	ru_translations "github.com/go-playground/validator/v10/translations/ru"
+	"golang.org/x/text/language"
+)
+
+// Languages of the message catalog.
+const (
+	LangEN = "en"
+	LangRU = "ru"
+)
+
+// Keys of the messages sent to clients.
+const (
+	MsgUserCreated        = "user_created"
+	MsgUserUpdated        = "user_updated"
+	MsgUserUpdatedPending = "user_updated_pending"
+	MsgUserDeleted        = "user_deleted"
+
+	msgBadRequest       = "bad_request"
+	msgInvalidUUID      = "invalid_uuid"
+	msgInvalidParam     = "invalid_param"
+	msgNotFound         = "not_found"
+	msgOverrideConflict = "override_conflict"
+	msgValidation       = "validation_failed"
+	msgUpstream         = "upstream_failed"
+	msgUnavailable      = "upstream_unavailable"
+	msgFieldInvalid     = "field_invalid"
+)
+
+// customTags are validation tags without a default validator translation,
+// their catalog message is keyed by the tag.
+var customTags = []string{"iso3166_1_alpha2"}
+
+var catalog = map[string]map[string]string{
+	LangEN: {
+		MsgUserCreated:        "user created successfully, enrichment is pending",
+		MsgUserUpdated:        "user updated successfully",
+		MsgUserUpdatedPending: "user updated successfully, enrichment is pending",
+		MsgUserDeleted:        "user deleted successfully",
+
+		msgBadRequest:       "request could not be read",
+		msgInvalidUUID:      "invalid UUID format",
+		msgInvalidParam:     "invalid value of parameter {0}",
+		msgNotFound:         "not found by id",
+		msgOverrideConflict: "field cannot be set and unlocked at the same time",
+		msgValidation:       "request validation failed",
+		msgUpstream:         "enrichment provider failed",
+		msgUnavailable:      "enrichment provider is unavailable",
+		msgFieldInvalid:     "{0} is not valid",
+		"iso3166_1_alpha2":  "{0} must be an ISO 3166-1 alpha-2 country code",
+
+		statusKey(http.StatusBadRequest):          "Bad Request",
+		statusKey(http.StatusNotFound):            "Not Found",
+		statusKey(http.StatusConflict):            "Conflict",
+		statusKey(http.StatusUnprocessableEntity): "Unprocessable Entity",
+		statusKey(http.StatusInternalServerError): "Internal Server Error",
+		statusKey(http.StatusBadGateway):          "Bad Gateway",
+		statusKey(http.StatusServiceUnavailable):  "Service Unavailable",
+	},
+	LangRU: {
+		MsgUserCreated:        "пользователь создан, обогащение данных в очереди",
+		MsgUserUpdated:        "пользователь обновлён",
+		MsgUserUpdatedPending: "пользователь обновлён, обогащение данных в очереди",
+		MsgUserDeleted:        "пользователь удалён",
+
+		msgBadRequest:       "не удалось прочитать запрос",
+		msgInvalidUUID:      "неверный формат UUID",
+		msgInvalidParam:     "недопустимое значение параметра {0}",
+		msgNotFound:         "запись с таким id не найдена",
+		msgOverrideConflict: "поле нельзя одновременно задать и разблокировать",
+		msgValidation:       "ошибка при валидации запроса",
+		msgUpstream:         "ошибка сервиса обогащения данных",
+		msgUnavailable:      "сервис обогащения данных недоступен",
+		msgFieldInvalid:     "{0} имеет недопустимое значение",
+		"iso3166_1_alpha2":  "{0} должен быть кодом страны ISO 3166-1 alpha-2",
+
+		statusKey(http.StatusBadRequest):          "Некорректный запрос",
+		statusKey(http.StatusNotFound):            "Не найдено",
+		statusKey(http.StatusConflict):            "Конфликт",
+		statusKey(http.StatusUnprocessableEntity): "Ошибка валидации",
+		statusKey(http.StatusInternalServerError): "Внутренняя ошибка сервера",
+		statusKey(http.StatusBadGateway):          "Ошибка внешнего сервиса",
+		statusKey(http.StatusServiceUnavailable):  "Сервис недоступен",
+	},
+}
+
+// errorMessages maps error kinds to their messages, more specific kinds
+// first.
+var errorMessages = []struct {
+	err error
+	key string
+}{
+	{ErrInvalidUUID, msgInvalidUUID},
+	{ErrBadRequest, msgBadRequest},
+	{ErrNotFoundById, msgNotFound},
+	{ErrOverrideConflict, msgOverrideConflict},
+	{ErrValidation, msgValidation},
+	{ErrUpstream, msgUpstream},
+	{ErrUnavailable, msgUnavailable},
+}
+
+var (
+	universal          *ut.UniversalTranslator
+	defaultTranslator  ut.Translator
+	validatorTranslate = map[string]func(*validator.Validate, ut.Translator) error{
+		LangEN: en_translations.RegisterDefaultTranslations,
+		LangRU: ru_translations.RegisterDefaultTranslations,
+	}
+)
+
+func init() {
+	universal = ut.New(en.New(), en.New(), ru.New())
+	for lang, messages := range catalog {
+		trans, _ := universal.GetTranslator(lang)
+		if err := registerCatalog(trans, messages); err != nil {
+			panic(fmt.Sprintf("api: register %s messages: %v", lang, err))
+		}
+	}
+	defaultTranslator, _ = universal.GetTranslator(LangEN)
+}
+
+func registerCatalog(trans ut.Translator, messages map[string]string) error {
+	for key, text := range messages {
+		if err := trans.Add(key, text, false); err != nil {
+			return err
+		}
+	}
+	if err := validatorTranslate[trans.Locale()](Validate, trans); err != nil {
+		return err
+	}
+	for _, tag := range customTags {
+		err := Validate.RegisterTranslation(tag, trans,
+			func(ut.Translator) error { return nil },
+			func(trans ut.Translator, fe validator.FieldError) string {
+				return translate(trans, fe.Tag(), fe.Field())
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDefaultLanguage picks the language used when Accept-Language names
+// none of the supported ones.
+func SetDefaultLanguage(lang string) error {
+	trans, ok := universal.GetTranslator(lang)
+	if !ok {
+		return fmt.Errorf("unsupported language %q, use %s or %s", lang, LangEN, LangRU)
+	}
+	defaultTranslator = trans
+	return nil
+}
+
+// Translator negotiates the language of r from its Accept-Language header.
+func Translator(r *http.Request) ut.Translator {
+	tags, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if trans, ok := universal.GetTranslator(base.String()); ok {
+			return trans
+		}
+	}
+	return defaultTranslator
+}
+
+// T returns the message key in the language of r.
+func T(r *http.Request, key string) string {
+	return translate(Translator(r), key)
+}
+
+func translate(trans ut.Translator, key string, params ...string) string {
+	msg, err := trans.T(key, params...)
+	if err != nil {
+		return key
+	}
+	return msg
+}
+
+// errorMessage describes the kind of err, or returns its text when the kind
+// has no message.
+func errorMessage(trans ut.Translator, err error) string {
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		return translate(trans, msgInvalidParam, paramErr.Param)
+	}
+	for _, m := range errorMessages {
+		if errors.Is(err, m.err) {
+			return translate(trans, m.key)
+		}
+	}
+	return err.Error()
+}
+
+func statusTitle(trans ut.Translator, status int) string {
+	if msg, err := trans.T(statusKey(status)); err == nil {
+		return msg
+	}
+	return http.StatusText(status)
+}
+
+func statusKey(status int) string {
+	return "status_" + strconv.Itoa(status)
+}
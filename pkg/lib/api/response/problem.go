@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+)
+
+const ContentTypeProblem = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 error body extended with the request ID and,
+// for invalid requests, the failed rule of every field.
+type ProblemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err for the client in the language of r. Client
+// errors carry the message of their kind, server errors only the kind so
+// internals do not leak.
+func NewProblem(r *http.Request, err error) ProblemDetails {
+	trans := Translator(r)
+	status := HTTPStatus(err)
+	problem := ProblemDetails{
+		Type:      "about:blank",
+		Title:     statusTitle(trans, status),
+		Status:    status,
+		Instance:  r.URL.Path,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		problem.Detail = translate(trans, msgValidation)
+		problem.Errors = FieldErrors(validationErrs, trans)
+	case status < http.StatusInternalServerError,
+		status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable:
+		problem.Detail = errorMessage(trans, err)
+	}
+	return problem
+}
+
+// RenderProblem writes err as application/problem+json with the status of
+// its kind.
+func RenderProblem(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("Content-Language", Translator(r).Locale())
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewProblem(t *testing.T) {
+	internal := errors.New("dial tcp 10.0.0.7:5432: connection refused")
+	tests := []struct {
+		name   string
+		lang   string
+		err    error
+		status int
+		title  string
+		detail string
+	}{
+		{"bad request", "", fmt.Errorf("%w: %w", ErrBadRequest, internal), http.StatusBadRequest, "Bad Request", "request could not be read"},
+		{"invalid parameter", "", &ParamError{Param: "page_size"}, http.StatusBadRequest, "Bad Request", "invalid value of parameter page_size"},
+		{"not found", "", ErrNotFoundById, http.StatusNotFound, "Not Found", "not found by id"},
+		{"conflict", "", ErrOverrideConflict, http.StatusConflict, "Conflict", "field cannot be set and unlocked at the same time"},
+		{"validation", "", ErrValidation, http.StatusUnprocessableEntity, "Unprocessable Entity", "request validation failed"},
+		{"upstream", "", fmt.Errorf("%w: %w", ErrUpstream, internal), http.StatusBadGateway, "Bad Gateway", "enrichment provider failed"},
+		{"unavailable", "", fmt.Errorf("%w: %w", ErrUnavailable, internal), http.StatusServiceUnavailable, "Service Unavailable", "enrichment provider is unavailable"},
+		{"internal error hides details", "", internal, http.StatusInternalServerError, "Internal Server Error", ""},
+		{"wrapped internal error hides details", "", fmt.Errorf("save user: %w", internal), http.StatusInternalServerError, "Internal Server Error", ""},
+		{"russian", "ru-RU,ru;q=0.9", ErrNotFoundById, http.StatusNotFound, "Не найдено", "запись с таким id не найдена"},
+		{"russian internal error", "ru", internal, http.StatusInternalServerError, "Внутренняя ошибка сервера", ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		if tt.lang != "" {
+			r.Header.Set("Accept-Language", tt.lang)
+		}
+		p := NewProblem(r, tt.err)
+		if p.Status != tt.status || p.Title != tt.title || p.Detail != tt.detail {
+			t.Errorf("%s: got %d %q %q, want %d %q %q",
+				tt.name, p.Status, p.Title, p.Detail, tt.status, tt.title, tt.detail)
+		}
+		if p.Type != "about:blank" || p.Instance != "/users/42" {
+			t.Errorf("%s: type %q, instance %q", tt.name, p.Type, p.Instance)
+		}
+	}
+}
+
+func TestRenderProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Accept-Language", "ru")
+	w := httptest.NewRecorder()
+	RenderProblem(w, r, errors.New("pq: password authentication failed for user admin"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want 500", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentTypeProblem {
+		t.Errorf("Content-Type %q, want %q", got, ContentTypeProblem)
+	}
+	if got := w.Header().Get("Content-Language"); got != LangRU {
+		t.Errorf("Content-Language %q, want %q", got, LangRU)
+	}
+	if strings.Contains(w.Body.String(), "password") {
+		t.Errorf("body leaks the error: %s", w.Body)
+	}
+	var p ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil || p.Status != http.StatusInternalServerError {
+		t.Errorf("body %s is not the problem: %v", w.Body, err)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validate checks request bodies. Errors name fields by their JSON key.
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// FieldError describes one failed rule of a request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// FieldErrors describes every failed rule in the language of trans, in the
+// order of the fields.
+func FieldErrors(errs validator.ValidationErrors, trans ut.Translator) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, FieldError{
+			Field:   err.Field(),
+			Rule:    err.Tag(),
+			Param:   err.Param(),
+			Message: fieldMessage(err, trans),
+		})
+	}
+	return fields
+}
+
+// fieldMessage translates err, rules without a translation get a generic
+// message.
+func fieldMessage(err validator.FieldError, trans ut.Translator) string {
+	if msg := err.Translate(trans); msg != err.Error() {
+		return msg
+	}
+	return translate(trans, msgFieldInvalid, err.Field())
+}
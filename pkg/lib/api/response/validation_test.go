@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type fieldErrorsRequest struct {
+	Name    string   `json:"name" validate:"required,min=2,max=5"`
+	Age     int      `json:"age,omitempty" validate:"gte=0,lte=120"`
+	Country string   `json:"country_hint" validate:"omitempty,iso3166_1_alpha2"`
+	Tags    []string `json:"tags" validate:"dive,max=3"`
+	NoTag   string   `validate:"omitempty,min=3"`
+}
+
+func TestFieldErrors(t *testing.T) {
+	en, _ := universal.GetTranslator(LangEN)
+	ru, _ := universal.GetTranslator(LangRU)
+	tests := []struct {
+		name string
+		req  fieldErrorsRequest
+		want []FieldError
+		ru   []string
+	}{
+		{
+			name: "upper bounds",
+			req:  fieldErrorsRequest{Name: "abcdef", Age: 130, Country: "XX", Tags: []string{"ok", "long"}},
+			want: []FieldError{
+				{Field: "name", Rule: "max", Param: "5", Message: "name must be a maximum of 5 characters in length"},
+				{Field: "age", Rule: "lte", Param: "120", Message: "age must be 120 or less"},
+				{Field: "country_hint", Rule: "iso3166_1_alpha2", Message: "country_hint must be an ISO 3166-1 alpha-2 country code"},
+				{Field: "tags[1]", Rule: "max", Param: "3", Message: "tags[1] must be a maximum of 3 characters in length"},
+			},
+			ru: []string{
+				"name должен содержать максимум 5 символов",
+				"age должен быть менее или равен 120",
+				"country_hint должен быть кодом страны ISO 3166-1 alpha-2",
+				"tags[1] должен содержать максимум 3 символа",
+			},
+		},
+		{
+			name: "lower bounds",
+			req:  fieldErrorsRequest{Name: "a", Age: -1, NoTag: "ab"},
+			want: []FieldError{
+				{Field: "name", Rule: "min", Param: "2", Message: "name must be at least 2 characters in length"},
+				{Field: "age", Rule: "gte", Param: "0", Message: "age must be 0 or greater"},
+				{Field: "NoTag", Rule: "min", Param: "3", Message: "NoTag must be at least 3 characters in length"},
+			},
+			ru: []string{
+				"name должен содержать минимум 2 символа",
+				"age должен быть больше или равно 0",
+				"NoTag должен содержать минимум 3 символа",
+			},
+		},
+		{
+			name: "required",
+			req:  fieldErrorsRequest{},
+			want: []FieldError{
+				{Field: "name", Rule: "required", Message: "name is a required field"},
+			},
+			ru: []string{"name обязательное поле"},
+		},
+	}
+	for _, tt := range tests {
+		var errs validator.ValidationErrors
+		if err := Validate.Struct(tt.req); !errors.As(err, &errs) {
+			t.Fatalf("%s: got %v, want validation errors", tt.name, err)
+		}
+		if got := FieldErrors(errs, en); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tt.name, got, tt.want)
+		}
+		for i, fe := range FieldErrors(errs, ru) {
+			if i < len(tt.ru) && fe.Message != tt.ru[i] {
+				t.Errorf("%s: ru message %q, want %q", tt.name, fe.Message, tt.ru[i])
+			}
+		}
+	}
+}